@@ -0,0 +1,306 @@
+// Package client calls the v1 HTTP API with typed requests and responses.
+// Error responses are returned as *Error, so callers can tell a missing partner
+// or invalid input from a failing server:
+//
+//	partner, err := c.GetPartner(ctx, 883)
+//	if errors.Is(err, client.ErrNotFound) { ... }
+package client
+
+import (
+	"aroundHome/app/dto"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout limits a single attempt of a call.
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetries is the number of times a call is repeated after a 5xx
+	// response or a network error.
+	DefaultRetries = 2
+	// DefaultBackoff is the wait before the first retry, it doubles with every retry.
+	DefaultBackoff = 100 * time.Millisecond
+)
+
+// Client calls the API at a base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with c instead of http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(client *Client) {
+		client.http = c
+	}
+}
+
+// WithTimeout limits every attempt of a call to d.
+func WithTimeout(d time.Duration) Option {
+	return func(client *Client) {
+		client.timeout = d
+	}
+}
+
+// WithRetries repeats a call up to n times after a 5xx response or a network
+// error, waiting backoff before the first retry and twice as long before every
+// further one. Calls that are not idempotent, like creating a partner, are
+// never repeated.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(client *Client) {
+		client.retries = n
+		client.backoff = backoff
+	}
+}
+
+// New returns a client of the API at baseURL, e.g. http://localhost:3000.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+		timeout: DefaultTimeout,
+		retries: DefaultRetries,
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetPartner returns the partner with id.
+func (c *Client) GetPartner(ctx context.Context, id int16) (*dto.Partner, error) {
+	partner := new(dto.Partner)
+	if err := c.do(ctx, http.MethodGet, partnerPath(id), nil, nil, partner); err != nil {
+		return nil, err
+	}
+	return partner, nil
+}
+
+// ListOptions filters and orders the partners of ListPartners. Zero values
+// don't filter.
+type ListOptions struct {
+	Ids []int16
+	// Materials the partners are experienced with all of.
+	Materials []string
+	// AnyMaterials the partners are experienced with any of.
+	AnyMaterials []string
+	MinRating    *float64
+	MaxRating    *float64
+	// NamePrefix is the start of the name, ignoring case.
+	NamePrefix string
+	// Sort is one of id, name, rating or -rating, id by default.
+	Sort string
+	// Limit is the page size, the API caps it at 200.
+	Limit int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+}
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if len(o.Ids) > 0 {
+		ids := make([]string, len(o.Ids))
+		for i, id := range o.Ids {
+			ids[i] = strconv.Itoa(int(id))
+		}
+		v.Set("ids", strings.Join(ids, ","))
+	}
+	setList(v, "materials", o.Materials)
+	setList(v, "materials_any", o.AnyMaterials)
+	if o.MinRating != nil {
+		v.Set("min_rating", formatFloat(*o.MinRating))
+	}
+	if o.MaxRating != nil {
+		v.Set("max_rating", formatFloat(*o.MaxRating))
+	}
+	setString(v, "name", o.NamePrefix)
+	setString(v, "sort", o.Sort)
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	setString(v, "cursor", o.Cursor)
+	return v
+}
+
+// ListPartners returns a page of the partners passing opts. Pass the
+// NextCursor of the page as Cursor, with the same filters and sort, to get the
+// next page.
+func (c *Client) ListPartners(ctx context.Context, opts ListOptions) (*dto.PartnerPage, error) {
+	page := new(dto.PartnerPage)
+	if err := c.do(ctx, http.MethodGet, "/v1/partners", opts.values(), nil, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// MatchRequest is a customer request to find partners for.
+type MatchRequest struct {
+	Lat       float64
+	Lng       float64
+	Materials []string
+	Sqm       float64
+	Phone     string
+}
+
+// Match returns the partners covering the location of r and experienced with
+// all its materials, best match first. Nothing is stored.
+func (c *Client) Match(ctx context.Context, r MatchRequest) (*dto.MatchResult, error) {
+	v := url.Values{}
+	v.Set("address", formatFloat(r.Lat)+","+formatFloat(r.Lng))
+	setList(v, "material", r.Materials)
+	if r.Sqm != 0 {
+		v.Set("sqm", formatFloat(r.Sqm))
+	}
+	setString(v, "phone", r.Phone)
+
+	result := new(dto.MatchResult)
+	if err := c.do(ctx, http.MethodGet, "/v1/query", v, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreatePartner stores a new partner and returns it as stored. Without an id
+// the next free id is assigned.
+func (c *Client) CreatePartner(ctx context.Context, partner dto.Partner) (*dto.Partner, error) {
+	created := new(dto.Partner)
+	if err := c.do(ctx, http.MethodPost, "/v1/partners", nil, partner, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdatePartner replaces all fields of the partner with the id of partner.
+func (c *Client) UpdatePartner(ctx context.Context, partner dto.Partner) (*dto.Partner, error) {
+	updated := new(dto.Partner)
+	if err := c.do(ctx, http.MethodPut, partnerPath(partner.Id), nil, partner, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// PatchPartner changes the given fields of the partner with id, keyed by their
+// JSON names like "rating", and returns the partner with all fields.
+func (c *Client) PatchPartner(ctx context.Context, id int16, fields map[string]interface{}) (*dto.Partner, error) {
+	patched := new(dto.Partner)
+	if err := c.do(ctx, http.MethodPatch, partnerPath(id), nil, fields, patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// DeletePartner deletes the partner with id.
+func (c *Client) DeletePartner(ctx context.Context, id int16) error {
+	return c.do(ctx, http.MethodDelete, partnerPath(id), nil, nil, nil)
+}
+
+// idempotent are the methods whose calls may be repeated.
+var idempotent = map[string]bool{http.MethodGet: true, http.MethodPut: true, http.MethodDelete: true}
+
+// do calls the API and decodes the response body into out, unless out is nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, u, body, out)
+		if !idempotent[method] || attempt >= c.retries || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, u string, body []byte, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, req.URL.Path, err)
+	}
+	return nil
+}
+
+// retryable reports whether a call failing with err may succeed when repeated.
+func retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if e, ok := err.(*Error); ok {
+		return e.Status >= http.StatusInternalServerError
+	}
+	// the request did not get an answer
+	_, ok := err.(*url.Error)
+	return ok
+}
+
+func partnerPath(id int16) string {
+	return "/v1/partners/" + strconv.Itoa(int(id))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func setString(v url.Values, key, value string) {
+	if value != "" {
+		v.Set(key, value)
+	}
+}
+
+func setList(v url.Values, key string, values []string) {
+	if len(values) > 0 {
+		v.Set(key, strings.Join(values, ","))
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"aroundHome/app/validation"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Errors matched by errors.Is against an *Error with a status of the kind.
+var (
+	// ErrNotFound is a missing partner, request, lead or material.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is an existing id or an invalid lead transition.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid is an invalid parameter or body, see Error.Errors for the fields.
+	ErrInvalid = errors.New("invalid input")
+)
+
+// Error is an error response of the API, decoded from its problem details.
+type Error struct {
+	Status   int    `json:"status"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	// RequestId identifies the request in the logs of the server.
+	RequestId string `json:"request_id"`
+	// Errors are the invalid fields of the request.
+	Errors validation.Errors `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return e.Title
+	}
+	return e.Title + ": " + e.Detail
+}
+
+// Is matches ErrNotFound, ErrConflict and ErrInvalid by the status of e.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrConflict:
+		return e.Status == http.StatusConflict
+	case ErrInvalid:
+		return e.Status == http.StatusBadRequest || e.Status == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// decodeError returns the error of a response with an error status. Bodies
+// that are no problem details, e.g. of a proxy in front of the API, are
+// described by the status only.
+func decodeError(resp *http.Response) *Error {
+	e := new(Error)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || json.Unmarshal(body, e) != nil {
+		e = new(Error)
+	}
+	e.Status = resp.StatusCode
+	if e.Title == "" {
+		e.Title = http.StatusText(resp.StatusCode)
+	}
+	if e.RequestId == "" {
+		e.RequestId = resp.Header.Get("X-Request-ID")
+	}
+	return e
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/matching"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Materials splits the values of the repeated material flag, each of which
+// may hold several comma separated materials.
+func Materials(values []string) []string {
+	var materials []string
+	for _, v := range values {
+		materials = append(materials, strings.Split(v, ",")...)
+	}
+	return materials
+}
+
+// Match prints the partners matcher finds for request to w, best match first,
+// like GET /v1/query answers them.
+func Match(ctx context.Context, w io.Writer, out Output, matcher *matching.Service, request matching.Request) error {
+	recs, err := matcher.Match(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	result := dto.MatchResult{Sqm: request.Sqm, Matches: dto.FromMatches(recs)}
+	return out.print(w, result, func(t *table) {
+		t.row("ID", "NAME", "DISTANCE_KM", "SCORE", "RATING", "MATERIALS")
+		for _, m := range result.Matches {
+			t.row(m.Id, m.Name, fmt.Sprintf("%.2f", m.Distance.Value), fmt.Sprintf("%.3f", m.Score), m.Rating, strings.Join(m.Materials, ","))
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// Package commands prints the results of the commands of the command line,
+// like partner get and match. The commands take their repositories and the
+// writer to print to, so they run against in-memory repositories in tests.
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Output is how a command prints its result.
+type Output string
+
+const (
+	// Table aligns the result in columns for people to read.
+	Table Output = "table"
+	// JSON prints the result as indented JSON, like the v1 API answers it.
+	JSON Output = "json"
+)
+
+// ParseOutput returns the Output named by s.
+func ParseOutput(s string) (Output, error) {
+	switch o := Output(s); o {
+	case Table, JSON:
+		return o, nil
+	}
+	return "", fmt.Errorf("unknown output %q, use table or json", s)
+}
+
+// print writes v to w as indented JSON, or the rows rows adds to a table.
+func (o Output) print(w io.Writer, v interface{}, rows func(*table)) error {
+	if o == JSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	t := &table{w: tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)}
+	rows(t)
+	return t.w.Flush()
+}
+
+// table aligns the columns of its rows.
+type table struct {
+	w *tabwriter.Writer
+}
+
+func (t *table) row(values ...interface{}) {
+	for i, v := range values {
+		if i > 0 {
+			fmt.Fprint(t.w, "\t")
+		}
+		fmt.Fprint(t.w, v)
+	}
+	fmt.Fprintln(t.w)
+}
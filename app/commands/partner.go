@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/repository"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PartnerId parses the arguments of partner get, a single partner id.
+func PartnerId(args []string) (int16, error) {
+	if len(args) != 1 {
+		return 0, errors.New("partner get needs exactly one id")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("id must be an integer, got %q", args[0])
+	}
+	return int16(id), nil
+}
+
+// GetPartner prints the partner with id to w, with the fields of the v1 API.
+func GetPartner(ctx context.Context, w io.Writer, out Output, partners repository.PartnerRepository, id int16) error {
+	rec, err := partners.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("partner %d not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	partner := dto.FromPartner(rec)
+	return out.print(w, partner, func(t *table) {
+		t.row("ID", partner.Id)
+		t.row("NAME", partner.Name)
+		t.row("LAT", partner.Lat)
+		t.row("LNG", partner.Lng)
+		t.row("RADIUS_KM", partner.RadiusKm)
+		t.row("RATING", partner.Rating)
+		t.row("MATERIALS", strings.Join(partner.Materials, ","))
+		t.row("MIN_SQM", partner.MinSqm)
+		t.row("MAX_SQM", partner.MaxSqm)
+	})
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"aroundHome/app/drain"
+	"aroundHome/app/exporter"
+	"aroundHome/app/logging"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"bufio"
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"io"
+)
+
+// exportFunc streams the partners in a format, like exporter.Write.
+type exportFunc func(context.Context, io.Writer, repository.PartnerRepository, exporter.Format) error
+
+// ExportHandler godoc
+// @Summary Export all partners.
+// @Description Streams every partner as CSV, NDJSON or GeoJSON, where each partner is a Point feature with its radius in kilometers in the properties.
+// @Tags partners
+// @Accept */*
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/geo+json
+// @Param format query string false "Export format" Enums(csv,ndjson,geojson) default(csv)
+// @Success 200 {string} string
+// @Failure 400 {object} problem.Problem
+// @Router /partners/export [get]
+func ExportHandler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	return export(c, partners, exporter.Write)
+}
+
+// ExportV1Handler godoc
+// @Summary Export all partners.
+// @Description Streams every partner as CSV, NDJSON or GeoJSON with the fields of the partners of the v1 API, radius_km and materials. In GeoJSON each partner is a Point feature with these fields in the properties.
+// @Tags v1
+// @Accept */*
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/geo+json
+// @Param format query string false "Export format" Enums(csv,ndjson,geojson) default(csv)
+// @Success 200 {string} string
+// @Failure 400 {object} problem.Problem
+// @Router /v1/partners/export [get]
+func ExportV1Handler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	return export(c, partners, exporter.WriteV1)
+}
+
+func export(c *fiber.Ctx, partners repository.PartnerRepository, write exportFunc) error {
+	format, err := exporter.ParseFormat(c.Query("format", string(exporter.CSV)))
+	if err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	c.Attachment("partners." + string(format))
+	c.Set(fiber.HeaderContentType, format.ContentType())
+
+	// the body is written after the handler returns, so c must not be used
+	// inside, and the request stays in flight for the shutdown until then
+	ctx := c.UserContext()
+	release := drain.Hold(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer release()
+		if err := write(ctx, w, partners, format); err != nil {
+			// the status is already sent, the client sees a truncated body
+			logging.Error(ctx, "export failed", "format", string(format), "error", err)
+		}
+	})
+
+	return nil
+}
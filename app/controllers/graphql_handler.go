@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"aroundHome/app/graph"
+	"aroundHome/app/problem"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GraphQLHandler godoc
+// @Summary Query partners and matches with GraphQL.
+// @Description Executes a query of partner(id), partners(filter, first, after) and match(location, materials, sqm). Errors are reported in the errors of the response with a code in their extensions. Queries nested deeper than 6 fields or estimated to resolve more than 2000 fields are rejected.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graph.Request true "GraphQL request"
+// @Success 200 {object} graph.Response
+// @Failure 400 {object} problem.Problem
+// @Router /graphql [post]
+func GraphQLHandler(c *fiber.Ctx, schema *graph.Schema) error {
+	req := new(graph.Request)
+	if err := c.BodyParser(req); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	return executeGraphQL(c, schema, req)
+}
+
+// GraphQLGetHandler godoc
+// @Summary Query partners and matches with GraphQL.
+// @Description Executes a query sent as query parameters, like POST /graphql.
+// @Tags graphql
+// @Accept */*
+// @Produce json
+// @Param query query string true "GraphQL query" example({partner(id: 1) {name}})
+// @Param operationName query string false "Operation to execute if the query holds several"
+// @Param variables query string false "Variables as JSON object"
+// @Success 200 {object} graph.Response
+// @Failure 400 {object} problem.Problem
+// @Router /graphql [get]
+func GraphQLGetHandler(c *fiber.Ctx, schema *graph.Schema) error {
+	req := &graph.Request{Query: c.Query("query"), OperationName: c.Query("operationName")}
+	if v := c.Query("variables"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+			return problem.BadRequest("variables must be a JSON object")
+		}
+	}
+	return executeGraphQL(c, schema, req)
+}
+
+func executeGraphQL(c *fiber.Ctx, schema *graph.Schema, req *graph.Request) error {
+	if req.Query == "" {
+		return problem.BadRequest("query is required")
+	}
+	if err := c.JSON(schema.Execute(c.UserContext(), *req)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,19 +1,28 @@
 package controllers
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"aroundHome/app/health"
+	"aroundHome/app/logging"
+	"aroundHome/app/version"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Status is the answer of HealthCheck.
+type Status struct {
+	Data string `json:"data" example:"Server is up and running"`
+}
 
 // HealthCheck godoc
 // @Summary Show the status of server.
-// @Description get the status of server.
+// @Description Answers as long as the process serves requests, without checking its dependencies. Use it as liveness probe and /readyz as readiness probe.
 // @Tags root
 // @Accept */*
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} Status
 // @Router / [get]
+// @Router /healthz [get]
 func HealthCheck(c *fiber.Ctx) error {
-	res := map[string]interface{}{
-		"data": "Server is up and running",
-	}
+	res := Status{Data: "Server is up and running"}
 
 	if err := c.JSON(res); err != nil {
 		return err
@@ -21,3 +30,40 @@ func HealthCheck(c *fiber.Ctx) error {
 
 	return nil
 }
+
+// ReadyHandler godoc
+// @Summary Show whether the server is ready to answer requests.
+// @Description Checks that the database answers a ping, that its schema is not older than the binary expects and that the in-memory partner index was refreshed within three refresh intervals when matching in memory. Every check has 2 seconds; the result of each is answered by name.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func ReadyHandler(c *fiber.Ctx, checks []health.Check) error {
+	report := health.Run(c.UserContext(), checks, health.DefaultTimeout)
+	if !report.Ready() {
+		logging.Warn(c.UserContext(), "not ready", "checks", report.Checks)
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	if err := c.JSON(report); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VersionHandler godoc
+// @Summary Show the build of the server.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Success 200 {object} version.Info
+// @Router /version [get]
+func VersionHandler(c *fiber.Ctx) error {
+	if err := c.JSON(version.Get()); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/importer"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"aroundHome/app/validation"
+	"bytes"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"io"
+)
+
+// readFunc parses the partners of a file, like importer.Read.
+type readFunc func(io.Reader, importer.Format) ([]importer.Row, error)
+
+// ImportHandler godoc
+// @Summary Import partners from a CSV, JSON or NDJSON file.
+// @Description Validates every row of the request body against the material catalog and upserts the valid partners in batches inside a single transaction, so either all of them are imported or none. Rows with errors are skipped and listed in the report.
+// @Tags partners
+// @Accept plain
+// @Produce json
+// @Param format query string false "File format" Enums(csv,json,ndjson) default(csv)
+// @Param dry_run query bool false "Only validate, do not write anything"
+// @Success 200 {object} importer.Report
+// @Failure 400 {object} problem.Problem
+// @Router /partners/import [post]
+func ImportHandler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	report, err := importPartners(c, partners, materials, importer.Read)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(report); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ImportV1Handler godoc
+// @Summary Import partners from a CSV, JSON or NDJSON file.
+// @Description Reads partners with the fields of the partners of the v1 API, radius_km and materials, validates every row against the material catalog and upserts the valid partners in batches inside a single transaction, so either all of them are imported or none. Rows with errors are skipped and listed in the report, by the fields of the v1 API.
+// @Tags v1
+// @Accept plain
+// @Produce json
+// @Param format query string false "File format" Enums(csv,json,ndjson) default(csv)
+// @Param dry_run query bool false "Only validate, do not write anything"
+// @Success 200 {object} importer.Report
+// @Failure 400 {object} problem.Problem
+// @Router /v1/partners/import [post]
+func ImportV1Handler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	report, err := importPartners(c, partners, materials, importer.ReadV1)
+	if err != nil {
+		return err
+	}
+	// the catalog checks report the fields of models.Partner
+	for i, failed := range report.Failed {
+		var errs validation.Errors
+		if err := dto.PartnerErrors(failed.Errors); errors.As(err, &errs) {
+			report.Failed[i].Errors = errs
+		}
+	}
+	if err := c.JSON(report); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func importPartners(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store, read readFunc) (*importer.Report, error) {
+	format, err := importer.ParseFormat(c.Query("format", string(importer.CSV)))
+	if err != nil {
+		return nil, problem.BadRequest(err.Error())
+	}
+	rows, err := read(bytes.NewReader(c.Body()), format)
+	if err != nil {
+		return nil, problem.BadRequest(err.Error())
+	}
+	catalog, err := materials.Catalog(c.UserContext())
+	if err != nil {
+		return nil, err
+	}
+	return importer.Import(c.UserContext(), partners, catalog, rows, importer.Options{DryRun: c.Query("dry_run") == "true"})
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"aroundHome/app/leads"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLeadsHandler godoc
+// @Summary List the leads of a customer request.
+// @Description Returns the partners the request was offered to and their answers, ordered by rank.
+// @Tags leads
+// @Accept */*
+// @Produce json
+// @Param id path int true "Request ID"
+// @Success 200 {array} models.Lead
+// @Router /requests/{id}/leads [get]
+func RequestLeadsHandler(c *fiber.Ctx, requests repository.RequestRepository, leadRepository repository.LeadRepository) error {
+	recs, err := requestLeads(c, requests, leadRepository)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(recs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LeadHandler godoc
+// @Summary Get a lead.
+// @Tags leads
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} models.Lead
+// @Router /leads/{id} [get]
+func LeadHandler(c *fiber.Ctx, leadRepository repository.LeadRepository) error {
+	rec, err := getLead(c, leadRepository)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(rec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ViewLeadHandler godoc
+// @Summary Mark a lead as viewed by the partner.
+// @Description Only offered leads can be viewed, other states answer 409.
+// @Tags leads
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} models.Lead
+// @Router /leads/{id}/view [post]
+func ViewLeadHandler(c *fiber.Ctx, dispatcher *leads.Dispatcher) error {
+	rec, err := leadTransition(c, dispatcher.View)
+	if err != nil {
+		return err
+	}
+	return c.JSON(rec)
+}
+
+// AcceptLeadHandler godoc
+// @Summary Accept a lead for the partner.
+// @Description Offered and viewed leads can be accepted until they expire, other states answer 409. Once enough partners have accepted, the other open leads of the request expire.
+// @Tags leads
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} models.Lead
+// @Router /leads/{id}/accept [post]
+func AcceptLeadHandler(c *fiber.Ctx, dispatcher *leads.Dispatcher) error {
+	rec, err := leadTransition(c, dispatcher.Accept)
+	if err != nil {
+		return err
+	}
+	return c.JSON(rec)
+}
+
+// DeclineLeadHandler godoc
+// @Summary Decline a lead for the partner.
+// @Description Offered and viewed leads can be declined until they expire, other states answer 409. The request is offered to the next partner in rank.
+// @Tags leads
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} models.Lead
+// @Router /leads/{id}/decline [post]
+func DeclineLeadHandler(c *fiber.Ctx, dispatcher *leads.Dispatcher) error {
+	rec, err := leadTransition(c, dispatcher.Decline)
+	if err != nil {
+		return err
+	}
+	return c.JSON(rec)
+}
+
+func requestLeads(c *fiber.Ctx, requests repository.RequestRepository, leadRepository repository.LeadRepository) ([]*models.Lead, error) {
+	id, err := int64Param(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requests.Get(c.UserContext(), id); err != nil {
+		return nil, err
+	}
+	return leadRepository.ForRequest(c.UserContext(), id)
+}
+
+func getLead(c *fiber.Ctx, leadRepository repository.LeadRepository) (*models.Lead, error) {
+	id, err := int64Param(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	return leadRepository.Get(c.UserContext(), id)
+}
+
+func leadTransition(c *fiber.Ctx, transition func(ctx context.Context, id int64) (*models.Lead, error)) (*models.Lead, error) {
+	id, err := int64Param(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	return transition(c.UserContext(), id)
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/leads"
+	"aroundHome/app/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLeadsV1Handler godoc
+// @Summary List the leads of a customer request.
+// @Description Returns the partners the request was offered to and their answers, ordered by rank.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Request ID"
+// @Success 200 {array} dto.Lead
+// @Failure 404 {object} problem.Problem
+// @Router /v1/requests/{id}/leads [get]
+func RequestLeadsV1Handler(c *fiber.Ctx, requests repository.RequestRepository, leadRepository repository.LeadRepository) error {
+	recs, err := requestLeads(c, requests, leadRepository)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromLeads(recs)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LeadV1Handler godoc
+// @Summary Get a lead.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} dto.Lead
+// @Failure 404 {object} problem.Problem
+// @Router /v1/leads/{id} [get]
+func LeadV1Handler(c *fiber.Ctx, leadRepository repository.LeadRepository) error {
+	rec, err := getLead(c, leadRepository)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromLead(rec)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ViewLeadV1Handler godoc
+// @Summary Mark a lead as viewed by the partner.
+// @Description Only offered leads can be viewed, other states answer 409.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} dto.Lead
+// @Failure 409 {object} problem.Problem
+// @Router /v1/leads/{id}/view [post]
+func ViewLeadV1Handler(c *fiber.Ctx, dispatcher *leads.Dispatcher) error {
+	rec, err := leadTransition(c, dispatcher.View)
+	if err != nil {
+		return err
+	}
+	return c.JSON(dto.FromLead(rec))
+}
+
+// AcceptLeadV1Handler godoc
+// @Summary Accept a lead for the partner.
+// @Description Offered and viewed leads can be accepted until they expire, other states answer 409. Once enough partners have accepted, the other open leads of the request expire.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} dto.Lead
+// @Failure 409 {object} problem.Problem
+// @Router /v1/leads/{id}/accept [post]
+func AcceptLeadV1Handler(c *fiber.Ctx, dispatcher *leads.Dispatcher) error {
+	rec, err := leadTransition(c, dispatcher.Accept)
+	if err != nil {
+		return err
+	}
+	return c.JSON(dto.FromLead(rec))
+}
+
+// DeclineLeadV1Handler godoc
+// @Summary Decline a lead for the partner.
+// @Description Offered and viewed leads can be declined until they expire, other states answer 409. The request is offered to the next partner in rank.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Lead ID"
+// @Success 200 {object} dto.Lead
+// @Failure 409 {object} problem.Problem
+// @Router /v1/leads/{id}/decline [post]
+func DeclineLeadV1Handler(c *fiber.Ctx, dispatcher *leads.Dispatcher) error {
+	rec, err := leadTransition(c, dispatcher.Decline)
+	if err != nil {
+		return err
+	}
+	return c.JSON(dto.FromLead(rec))
+}
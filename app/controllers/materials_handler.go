@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/problem"
+	"aroundHome/app/taxonomy"
+	"aroundHome/app/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListMaterialsHandler godoc
+// @Summary List the material catalog.
+// @Description Returns all materials including inactive ones and categories.
+// @Tags materials
+// @Accept */*
+// @Produce json
+// @Success 200 {array} models.Material
+// @Router /admin/materials [get]
+func ListMaterialsHandler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	recs, err := materials.List(c.UserContext())
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(recs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMaterialHandler godoc
+// @Summary Get a material of the catalog.
+// @Tags materials
+// @Accept */*
+// @Produce json
+// @Param code path string true "Material code"
+// @Success 200 {object} models.Material
+// @Router /admin/materials/{code} [get]
+func GetMaterialHandler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	rec, err := materials.Get(c.UserContext(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(rec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutMaterialHandler godoc
+// @Summary Create or replace a material of the catalog.
+// @Description Setting Active to false stops the material from being requested or assigned to partners. Parent groups the material under a category.
+// @Tags materials
+// @Accept json
+// @Produce json
+// @Param code path string true "Material code"
+// @Param material body models.Material true "Material, the code is taken from the path"
+// @Success 200 {object} models.Material
+// @Router /admin/materials/{code} [put]
+func PutMaterialHandler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	rec := new(models.Material)
+	if err := c.BodyParser(rec); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	if err := putMaterial(c, materials, rec); err != nil {
+		return err
+	}
+	if err := c.JSON(rec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMaterialHandler godoc
+// @Summary Delete a material of the catalog.
+// @Description Categories can only be deleted once no material belongs to them. To keep a material for existing partners but stop offering it, set Active to false instead.
+// @Tags materials
+// @Accept */*
+// @Param code path string true "Material code"
+// @Success 204
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /admin/materials/{code} [delete]
+// @Router /v1/admin/materials/{code} [delete]
+func DeleteMaterialHandler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	if err := materials.Delete(c.UserContext(), c.Params("code")); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// putMaterial validates and stores rec with the code taken from the path.
+func putMaterial(c *fiber.Ctx, materials *taxonomy.Store, rec *models.Material) error {
+	rec.Code = c.Params("code")
+	catalog, err := materials.Catalog(c.UserContext())
+	if err != nil {
+		return err
+	}
+	if err := validation.Material(rec, catalog); err != nil {
+		return err
+	}
+	return materials.Upsert(c.UserContext(), rec)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/problem"
+	"aroundHome/app/taxonomy"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListMaterialsV1Handler godoc
+// @Summary List the material catalog.
+// @Description Returns all materials including inactive ones and categories.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Success 200 {array} dto.Material
+// @Router /v1/admin/materials [get]
+func ListMaterialsV1Handler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	recs, err := materials.List(c.UserContext())
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromMaterials(recs)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMaterialV1Handler godoc
+// @Summary Get a material of the catalog.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param code path string true "Material code"
+// @Success 200 {object} dto.Material
+// @Failure 404 {object} problem.Problem
+// @Router /v1/admin/materials/{code} [get]
+func GetMaterialV1Handler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	rec, err := materials.Get(c.UserContext(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromMaterial(rec)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutMaterialV1Handler godoc
+// @Summary Create or replace a material of the catalog.
+// @Description Setting active to false stops the material from being requested or assigned to partners. The parent groups the material under a category.
+// @Tags v1
+// @Accept json
+// @Produce json
+// @Param code path string true "Material code"
+// @Param material body dto.Material true "Material, the code is taken from the path"
+// @Success 200 {object} dto.Material
+// @Failure 422 {object} problem.Problem
+// @Router /v1/admin/materials/{code} [put]
+func PutMaterialV1Handler(c *fiber.Ctx, materials *taxonomy.Store) error {
+	body := new(dto.Material)
+	if err := c.BodyParser(body); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	rec := body.Model()
+	if err := putMaterial(c, materials, rec); err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromMaterial(rec)); err != nil {
+		return err
+	}
+
+	return nil
+}
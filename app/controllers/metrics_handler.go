@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"aroundHome/app/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+// metricsHandler answers the metrics registered with the default registry.
+var metricsHandler = metrics.Handler()
+
+// MetricsHandler godoc
+// @Summary Show the metrics of the server.
+// @Description Answers the requests by route and status, their latency, the database pool, the matching and the Go runtime in the Prometheus text format, for scraping by Prometheus.
+// @Tags root
+// @Accept */*
+// @Produce plain
+// @Success 200 {string} string
+// @Router /metrics [get]
+func MetricsHandler(c *fiber.Ctx) error {
+	return metricsHandler(c)
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OpenAPIHandler godoc
+// @Summary Get the OpenAPI 3 description of the API.
+// @Description The description is generated from the handlers and served by the binary, so it always matches the running version.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Success 200 {object} object
+// @Router /openapi.json [get]
+func OpenAPIHandler(c *fiber.Ctx, doc *openapi3.T) error {
+	if err := c.JSON(doc); err != nil {
+		return err
+	}
+
+	return nil
+}
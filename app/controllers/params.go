@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"aroundHome/app/problem"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+)
+
+// int64Param parses the path parameter name as an integer.
+func int64Param(c *fiber.Ctx, name string) (int64, error) {
+	v, err := strconv.ParseInt(c.Params(name), 10, 64)
+	if err != nil {
+		return 0, problem.BadRequest(name + " must be an integer")
+	}
+	return v, nil
+}
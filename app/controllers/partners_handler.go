@@ -1,11 +1,17 @@
 package controllers
 
 import (
+	"aroundHome/app/geo"
+	"aroundHome/app/logging"
 	"aroundHome/app/models"
-	"database/sql"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"aroundHome/app/validation"
+	"errors"
 	"github.com/gofiber/fiber/v2"
-	"log"
 	"strconv"
+	"strings"
 )
 
 // PartnersHandler godoc
@@ -15,40 +21,341 @@ import (
 // @Accept */*
 // @Produce json
 // @Param id  path int true "Partner ID"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} models.Partner
+// @Failure 404 {object} problem.Problem
 // @Router /partners/{id} [get]
-func PartnersHandler(c *fiber.Ctx, db *sql.DB) error {
-	id, err := strconv.ParseInt(c.Params("id"), 10, 16)
+func PartnersHandler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	rec, err := getPartner(c, partners)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(rec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const (
+	// defaultListLimit is the page size of ListPartnersHandler without a limit.
+	defaultListLimit = 50
+	// maxListLimit caps the page size and the number of ids of ListPartnersHandler.
+	maxListLimit = 200
+)
+
+// ListPartnersHandler godoc
+// @Summary List partners page by page.
+// @Description Returns a page of partners passing all given filters. The response holds next_cursor as long as more partners follow; pass it as cursor, with the same filters and sort, to get the next page.
+// @Tags partners
+// @Accept */*
+// @Produce json
+// @Param ids query []int false "Only partners with these ids" collectionFormat(csv) example(1,883,805)
+// @Param materials query []string false "Partners experienced with all of these materials" collectionFormat(csv) example(carpet,tiles)
+// @Param materials_any query []string false "Partners experienced with any of these materials" collectionFormat(csv) example(wood,cement)
+// @Param min_rating query number false "Minimum rating" example(5)
+// @Param max_rating query number false "Maximum rating" example(10)
+// @Param name query string false "Start of the name, ignoring case" example(Mee)
+// @Param bbox query string false "Box containing the partner office as min_lng,min_lat,max_lng,max_lat" example(110,35,120,45)
+// @Param sort query string false "Order of the partners" Enums(id, name, rating, -rating) default(id)
+// @Param limit query int false "Page size, at most 200" default(50)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} PartnerList
+// @Failure 400 {object} problem.Problem
+// @Router /partners [get]
+func ListPartnersHandler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	filter, err := partnerFilter(c)
 	if err != nil {
 		return err
 	}
-	rows, err := db.Query(partnerSql(), id)
+	page, err := partners.Find(c.UserContext(), *filter)
 	if err != nil {
-		log.Fatal(err)
 		return err
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
+	logging.SetResults(c, len(page.Partners))
+	response := PartnerList{Partners: page.Partners}
+	if page.Next != nil {
+		response.NextCursor = page.Next.String()
+	}
+	if err := c.JSON(response); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PartnerList is a page of partners answered by ListPartnersHandler, NextCursor
+// is empty on the last page.
+type PartnerList struct {
+	Partners   []*models.Partner `json:"partners"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// partnerFilter reads the filter of ListPartnersHandler from the query string.
+func partnerFilter(c *fiber.Ctx) (*repository.PartnerFilter, error) {
+	var errs validation.Errors
+	filter := &repository.PartnerFilter{
+		Materials:    splitList(c.Query("materials")),
+		AnyMaterials: splitList(c.Query("materials_any")),
+		NamePrefix:   c.Query("name"),
+		Sort:         repository.SortId,
+		Limit:        defaultListLimit,
+	}
+
+	for _, v := range splitList(c.Query("ids")) {
+		id, err := strconv.ParseInt(v, 10, 16)
+		if err != nil {
+			errs.Add("ids", "must be a list of integers")
+			break
+		}
+		filter.Ids = append(filter.Ids, int16(id))
+	}
+	if len(filter.Ids) > maxListLimit {
+		errs.Add("ids", "must not hold more than %d ids", maxListLimit)
+	}
+	for _, rating := range []struct {
+		name  string
+		value **float32
+	}{{"min_rating", &filter.MinRating}, {"max_rating", &filter.MaxRating}} {
+		v := c.Query(rating.name)
+		if v == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 32)
 		if err != nil {
-			log.Fatal(err)
+			errs.Add(rating.name, "must be a number")
+			continue
 		}
-	}(rows)
-	recs := make([]*models.Partner, 0)
-	for rows.Next() {
-		rec := new(models.Partner)
-		err := rows.Scan(&rec.Id, &rec.Name, &rec.Lat, &rec.Lng, &rec.Radius, &rec.Rating, &rec.FlooringExperience)
+		r := float32(f)
+		*rating.value = &r
+	}
+	if v := c.Query("bbox"); v != "" {
+		box, err := parseBox(v)
 		if err != nil {
-			log.Fatal(err)
+			errs.Add("bbox", "%v", err)
 		}
-		recs = append(recs, rec)
+		filter.Box = box
+	}
+	if v := c.Query("sort"); v != "" {
+		sort, err := repository.ParseSort(v)
+		if err != nil {
+			errs.Add("sort", "%v", err)
+		}
+		filter.Sort = sort
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			errs.Add("limit", "must be a positive integer")
+		}
+		if limit > maxListLimit {
+			limit = maxListLimit
+		}
+		filter.Limit = limit
+	}
+	// an unknown sort is reported above, its cursor cannot be checked
+	if v := c.Query("cursor"); v != "" && filter.Sort != "" {
+		cursor, err := repository.ParseCursor(v, filter.Sort)
+		if err != nil {
+			errs.Add("cursor", "must be the next_cursor of a page with the same sort")
+		}
+		filter.After = cursor
+	}
+
+	if len(errs) > 0 {
+		return nil, problem.InvalidParameters(errs)
+	}
+	return filter, nil
+}
+
+// parseBox parses min_lng,min_lat,max_lng,max_lat like a GeoJSON bbox. A box
+// with min_lng greater than max_lng crosses the antimeridian.
+func parseBox(s string) (*geo.Box, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("must be min_lng,min_lat,max_lng,max_lat")
+	}
+	values := make([]float64, 4)
+	for i, v := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, errors.New("must be min_lng,min_lat,max_lng,max_lat")
+		}
+		values[i] = f
+	}
+	box := &geo.Box{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	if box.MinLat > box.MaxLat || box.MinLat < -90 || box.MaxLat > 90 || box.MinLng < -180 || box.MaxLng > 180 {
+		return nil, errors.New("latitudes must be between -90 and 90 and ordered, longitudes between -180 and 180")
+	}
+	return box, nil
+}
+
+// splitList splits a comma separated query value, an empty value is an empty list.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	values := strings.Split(s, ",")
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+// CreatePartnerHandler godoc
+// @Summary Create a partner.
+// @Description Validates and stores a new partner. Without an Id the next free id is assigned. FlooringExperience is a list of materials from the catalog like {carpet,tiles}.
+// @Tags partners
+// @Accept json
+// @Produce json
+// @Param partner body models.Partner true "Partner"
+// @Success 201 {object} models.Partner
+// @Failure 422 {object} problem.Problem
+// @Router /partners [post]
+func CreatePartnerHandler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	rec := new(models.Partner)
+	if err := c.BodyParser(rec); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	if err := createPartner(c, partners, materials, rec); err != nil {
+		return err
+	}
+	if err := c.Status(fiber.StatusCreated).JSON(rec); err != nil {
+		return err
 	}
-	if err := c.JSON(recs[0]); err != nil {
+
+	return nil
+}
+
+// UpdatePartnerHandler godoc
+// @Summary Replace a partner.
+// @Description Validates and replaces all fields of an existing partner, the id is taken from the path.
+// @Tags partners
+// @Accept json
+// @Produce json
+// @Param id path int true "Partner ID"
+// @Param partner body models.Partner true "Partner"
+// @Success 200 {object} models.Partner
+// @Failure 422 {object} problem.Problem
+// @Router /partners/{id} [put]
+func UpdatePartnerHandler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	id, err := partnerId(c)
+	if err != nil {
+		return err
+	}
+	rec := new(models.Partner)
+	if err := c.BodyParser(rec); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	rec.Id = id
+	if err := updatePartner(c, partners, materials, rec); err != nil {
+		return err
+	}
+	if err := c.JSON(rec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PatchPartnerHandler godoc
+// @Summary Change some fields of a partner.
+// @Description Fields missing from the body keep their value, the result is validated like a replaced partner.
+// @Tags partners
+// @Accept json
+// @Produce json
+// @Param id path int true "Partner ID"
+// @Param partner body models.Partner true "Fields to change"
+// @Success 200 {object} models.Partner
+// @Failure 422 {object} problem.Problem
+// @Router /partners/{id} [patch]
+func PatchPartnerHandler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	id, err := partnerId(c)
+	if err != nil {
+		return err
+	}
+	rec, err := partners.Get(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	// decoding into the stored partner keeps the fields missing from the body
+	if err := c.BodyParser(rec); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	rec.Id = id
+	if err := updatePartner(c, partners, materials, rec); err != nil {
+		return err
+	}
+	if err := c.JSON(rec); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func partnerSql() string {
-	return "select\n    Id, Name, Lat, Lng, Radius, Rating, flooring_experience AS FlooringExperience\nfrom\n    partners\nwhere\n    id = $1;"
+// DeletePartnerHandler godoc
+// @Summary Delete a partner.
+// @Tags partners
+// @Accept */*
+// @Param id path int true "Partner ID"
+// @Success 204
+// @Failure 404 {object} problem.Problem
+// @Router /partners/{id} [delete]
+// @Router /v1/partners/{id} [delete]
+func DeletePartnerHandler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	id, err := partnerId(c)
+	if err != nil {
+		return err
+	}
+	if err := partners.Delete(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func partnerId(c *fiber.Ctx) (int16, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 16)
+	if err != nil {
+		return 0, problem.BadRequest("id must be an integer")
+	}
+	return int16(id), nil
+}
+
+func getPartner(c *fiber.Ctx, partners repository.PartnerRepository) (*models.Partner, error) {
+	id, err := partnerId(c)
+	if err != nil {
+		return nil, err
+	}
+	return partners.Get(c.UserContext(), id)
+}
+
+func validatePartner(c *fiber.Ctx, rec *models.Partner, materials *taxonomy.Store) error {
+	catalog, err := materials.Catalog(c.UserContext())
+	if err != nil {
+		return err
+	}
+	return validation.Partner(rec, catalog)
+}
+
+// updatePartner validates and updates rec.
+func updatePartner(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store, rec *models.Partner) error {
+	if err := validatePartner(c, rec, materials); err != nil {
+		return err
+	}
+	return partners.Update(c.UserContext(), rec)
+}
+
+// createPartner validates and creates rec and points the Location header at it.
+func createPartner(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store, rec *models.Partner) error {
+	if rec.Id < 0 {
+		return validation.Errors{{Field: "id", Message: "must not be negative"}}
+	}
+	if err := validatePartner(c, rec, materials); err != nil {
+		return err
+	}
+	if err := partners.Create(c.UserContext(), rec); err != nil {
+		return err
+	}
+	c.Location(c.Path() + "/" + strconv.Itoa(int(rec.Id)))
+	return nil
 }
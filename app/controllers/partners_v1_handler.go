@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/logging"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PartnerV1Handler godoc
+// @Summary Get a partner.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Partner ID"
+// @Success 200 {object} dto.Partner
+// @Failure 404 {object} problem.Problem
+// @Router /v1/partners/{id} [get]
+func PartnerV1Handler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	rec, err := getPartner(c, partners)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromPartner(rec)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListPartnersV1Handler godoc
+// @Summary List partners page by page.
+// @Description Returns a page of partners passing all given filters. The response holds next_cursor as long as more partners follow; pass it as cursor, with the same filters and sort, to get the next page.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param ids query []int false "Only partners with these ids" collectionFormat(csv) example(1,883,805)
+// @Param materials query []string false "Partners experienced with all of these materials" collectionFormat(csv) example(carpet,tiles)
+// @Param materials_any query []string false "Partners experienced with any of these materials" collectionFormat(csv) example(wood,cement)
+// @Param min_rating query number false "Minimum rating" example(5)
+// @Param max_rating query number false "Maximum rating" example(10)
+// @Param name query string false "Start of the name, ignoring case" example(Mee)
+// @Param bbox query string false "Box containing the partner office as min_lng,min_lat,max_lng,max_lat" example(110,35,120,45)
+// @Param sort query string false "Order of the partners" Enums(id, name, rating, -rating) default(id)
+// @Param limit query int false "Page size, at most 200" default(50)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.PartnerPage
+// @Failure 400 {object} problem.Problem
+// @Router /v1/partners [get]
+func ListPartnersV1Handler(c *fiber.Ctx, partners repository.PartnerRepository) error {
+	filter, err := partnerFilter(c)
+	if err != nil {
+		return err
+	}
+	page, err := partners.Find(c.UserContext(), *filter)
+	if err != nil {
+		return err
+	}
+	logging.SetResults(c, len(page.Partners))
+	if err := c.JSON(dto.FromPartnerPage(page)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreatePartnerV1Handler godoc
+// @Summary Create a partner.
+// @Description Validates and stores a new partner. Without an id the next free id is assigned.
+// @Tags v1
+// @Accept json
+// @Produce json
+// @Param partner body dto.Partner true "Partner"
+// @Success 201 {object} dto.Partner
+// @Failure 409 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v1/partners [post]
+func CreatePartnerV1Handler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	body := new(dto.Partner)
+	if err := c.BodyParser(body); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	rec := body.Model()
+	if err := createPartner(c, partners, materials, rec); err != nil {
+		return dto.PartnerErrors(err)
+	}
+	if err := c.Status(fiber.StatusCreated).JSON(dto.FromPartner(rec)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePartnerV1Handler godoc
+// @Summary Replace a partner.
+// @Description Validates and replaces all fields of an existing partner, the id is taken from the path.
+// @Tags v1
+// @Accept json
+// @Produce json
+// @Param id path int true "Partner ID"
+// @Param partner body dto.Partner true "Partner"
+// @Success 200 {object} dto.Partner
+// @Failure 404 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v1/partners/{id} [put]
+func UpdatePartnerV1Handler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	id, err := partnerId(c)
+	if err != nil {
+		return err
+	}
+	body := new(dto.Partner)
+	if err := c.BodyParser(body); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	body.Id = id
+	return updatePartnerV1(c, partners, materials, body)
+}
+
+// PatchPartnerV1Handler godoc
+// @Summary Change some fields of a partner.
+// @Description Fields missing from the body keep their value, the result is validated like a replaced partner.
+// @Tags v1
+// @Accept json
+// @Produce json
+// @Param id path int true "Partner ID"
+// @Param partner body dto.Partner true "Fields to change"
+// @Success 200 {object} dto.Partner
+// @Failure 404 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v1/partners/{id} [patch]
+func PatchPartnerV1Handler(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store) error {
+	rec, err := getPartner(c, partners)
+	if err != nil {
+		return err
+	}
+	// decoding into the stored partner keeps the fields missing from the body
+	body := dto.FromPartner(rec)
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	body.Id = rec.Id
+	return updatePartnerV1(c, partners, materials, &body)
+}
+
+func updatePartnerV1(c *fiber.Ctx, partners repository.PartnerRepository, materials *taxonomy.Store, body *dto.Partner) error {
+	rec := body.Model()
+	if err := updatePartner(c, partners, materials, rec); err != nil {
+		return dto.PartnerErrors(err)
+	}
+	if err := c.JSON(dto.FromPartner(rec)); err != nil {
+		return err
+	}
+
+	return nil
+}
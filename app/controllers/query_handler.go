@@ -1,87 +1,82 @@
 package controllers
 
 import (
+	"aroundHome/app/logging"
+	"aroundHome/app/matching"
+	"aroundHome/app/metrics"
 	"aroundHome/app/models"
-	"database/sql"
+	"aroundHome/app/problem"
+	"aroundHome/app/validation"
 	"errors"
-	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"log"
 	"strconv"
 	"strings"
 )
 
 // QueryHandler godoc
 // @Summary Get list of partners that satisfy given query.
-// @Description Returns list of partners that satisfy given query.
+// @Description Returns list of partners that satisfy given query, best match first, each with its score and the contribution of every ranking factor. Nothing is stored, use POST /requests to keep the request.
 // @Tags query
 // @Accept */*
 // @Produce json
 // @Param phone  query string false "Phone number for contact" example(01604323444)
-// @Param sqm  query decimal false "Square meters" example(65.22)
+// @Param sqm  query number false "Square meters" minimum(0) example(65.22)
 // @Param address  query string true "Address in format: Latitude,Longitude" example(40.076763,113.30013)
-// @Param material query []string true "Material collection: carpet,tiles,wood" collectionFormat(csv) example(carpet,tiles,wood)
-// @Success 200 {object} map[string]interface{}
-// @Router /query/{id} [get]
-func QueryHandler(c *fiber.Ctx, db *sql.DB) error {
-	//qString := string(c.Request().URI().QueryString())
-	phone := c.Query("phone", "")
-	sqm := c.Query("sqm", "")
-	address := strings.Split(c.Query("address", "0,0"), ",")
-	lat, err := strconv.ParseFloat(address[0], 32)
+// @Param material query []string true "Material codes of the catalog, see /admin/materials" collectionFormat(csv) example(carpet,tiles,wood)
+// @Success 200 {object} QueryResult
+// @Failure 400 {object} problem.Problem
+// @Router /query [get]
+func QueryHandler(c *fiber.Ctx, matcher *matching.Service) error {
+	_, recs, err := query(c, matcher)
 	if err != nil {
 		return err
 	}
-	lng, err := strconv.ParseFloat(address[1], 32)
-	if err != nil {
+	response := QueryResult{Phone: c.Query("phone"), Partners: recs, Sqm: c.Query("sqm")}
+	if err := c.JSON(response); err != nil {
 		return err
 	}
-	material := strings.Split(c.Query("material"), ",")
-	// prevent SQL injection by strictly checking types of material
-	for _, v := range material {
-		if v != "carpet" && v != "tiles" && v != "wood" {
-			return errors.New("material " + v + " is not allowed in query")
+
+	return nil
+}
+
+// QueryResult is the answer of QueryHandler, Phone and Sqm echo the query.
+type QueryResult struct {
+	Phone    string                        `json:"phone"`
+	Partners []*models.PartnerWithDistance `json:"partners"`
+	Sqm      string                        `json:"sqm"`
+}
+
+// query matches the request described by the query string.
+func query(c *fiber.Ctx, matcher *matching.Service) (*matching.Request, []*models.PartnerWithDistance, error) {
+	var sqm float64
+	if v := c.Query("sqm", ""); v != "" {
+		var err error
+		if sqm, err = strconv.ParseFloat(v, 64); err != nil || sqm < 0 {
+			return nil, nil, problem.BadRequest("sqm must be a non-negative number")
 		}
 	}
-	// manually injecting materials into sql because JSON array is changed during injection into db.Query
-	materialString := "'" + strings.Join(material, `','`) + "'"
-	rows, err := db.Query(querySql(materialString), lat, lng)
+	address := strings.Split(c.Query("address", "0,0"), ",")
+	if len(address) != 2 {
+		return nil, nil, problem.BadRequest("address must be in format latitude,longitude")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(address[0]), 64)
 	if err != nil {
-		return err
+		return nil, nil, problem.BadRequest("latitude of the address must be a number")
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-
-		}
-	}(rows)
-
-	recs := make([]*models.PartnerWithDistance, 0)
-	for rows.Next() {
-		rec := new(models.PartnerWithDistance)
-		err := rows.Scan(&rec.Partner.Id, &rec.Partner.Name, &rec.Partner.Lat, &rec.Partner.Lng, &rec.Partner.Radius, &rec.Partner.Rating, &rec.Partner.FlooringExperience, &rec.Distance)
-		//e, err := json.Marshal(rec)
-		//if err != nil {
-		//	log.Fatal(err)
-		//}
-		//log.Fatal(e)
-		if err != nil {
-			log.Fatal(err)
-		}
-		recs = append(recs, rec)
+	lng, err := strconv.ParseFloat(strings.TrimSpace(address[1]), 64)
+	if err != nil {
+		return nil, nil, problem.BadRequest("longitude of the address must be a number")
 	}
-	response := map[string]interface{}{
-		"phone":    phone,
-		"partners": recs,
-		"sqm":      sqm,
+	request := &matching.Request{Lat: lat, Lng: lng, Materials: strings.Split(c.Query("material"), ","), Sqm: sqm}
+	recs, err := matcher.Match(c.UserContext(), *request)
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		return nil, nil, problem.InvalidParameters(errs)
 	}
-	if err := c.JSON(response); err != nil {
-		return err
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return nil
-}
-
-func querySql(materialString string) string {
-	return fmt.Sprintf("select\n    Id, Name, Lat, Lng, Radius, Rating, flooring_experience AS FlooringExperience,\n    getDistance($1, $2, Lat, Lng) AS Distance\nfrom\n    partners\nwhere\n    getDistance($1, $2, Lat, Lng) < Radius AND flooring_experience @> ARRAY[%s]\norder by\n    Rating DESC,\n    Distance;", materialString)
+	metrics.ObserveMatch(request.Materials, len(recs))
+	logging.SetResults(c, len(recs))
+	return request, recs, nil
 }
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/logging"
+	"aroundHome/app/matching"
+	"aroundHome/app/metrics"
+	"aroundHome/app/problem"
+	"aroundHome/app/validation"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+)
+
+// maxBatchSize caps the number of queries of QueryBatchV1Handler.
+const maxBatchSize = 1000
+
+// QueryV1Handler godoc
+// @Summary Get list of partners that satisfy given query.
+// @Description Returns the partners covering the address and experienced with every material, best match first, each with its distance, score and the contribution of every ranking factor. Nothing is stored, use POST /v1/requests to keep the request.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param phone query string false "Phone number for contact" example(01604323444)
+// @Param sqm query number false "Square meters" minimum(0) example(65.22)
+// @Param address query string true "Address in format: Latitude,Longitude" example(40.076763,113.30013)
+// @Param material query []string true "Material codes of the catalog, see /v1/admin/materials" collectionFormat(csv) example(carpet,tiles,wood)
+// @Success 200 {object} dto.MatchResult
+// @Failure 400 {object} problem.Problem
+// @Router /v1/query [get]
+func QueryV1Handler(c *fiber.Ctx, matcher *matching.Service) error {
+	request, recs, err := query(c, matcher)
+	if err != nil {
+		return err
+	}
+	result := dto.MatchResult{Phone: c.Query("phone"), Sqm: request.Sqm, Matches: dto.FromMatches(recs)}
+	if err := c.JSON(result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// QueryBatchV1Handler godoc
+// @Summary Match many customer requests at once.
+// @Description Matches up to 1000 queries like GET /v1/query and returns their results by id. A query that cannot be matched gets the problem details of its error instead of matches, the other queries are matched anyway. Nothing is stored.
+// @Tags v1
+// @Accept json
+// @Produce json
+// @Param batch body dto.BatchQuery true "Queries with unique ids"
+// @Success 200 {object} dto.BatchResult
+// @Failure 400 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v1/query/batch [post]
+func QueryBatchV1Handler(c *fiber.Ctx, matcher *matching.Service) error {
+	body := new(dto.BatchQuery)
+	if err := c.BodyParser(body); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	if err := validateBatch(body); err != nil {
+		return err
+	}
+
+	requests := make([]matching.Request, len(body.Requests))
+	for i, r := range body.Requests {
+		requests[i] = r.Request()
+	}
+	results := matcher.MatchBatch(c.UserContext(), requests, matching.DefaultConcurrency)
+	matches := 0
+	for i, result := range results {
+		switch {
+		case result.Err == nil:
+			metrics.ObserveMatch(requests[i].Materials, len(result.Matches))
+			matches += len(result.Matches)
+		case problem.From(result.Err).Status >= fiber.StatusInternalServerError:
+			// the problem details of the query hide the error like problem.Handler does
+			logging.Error(c.UserContext(), "query failed", "query", body.Requests[i].Id, "error", result.Err)
+		}
+	}
+	logging.SetResults(c, matches)
+	if err := c.JSON(dto.FromBatchResult(body.Requests, results)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateBatch checks the size of the batch and the ids of its queries. The
+// queries themselves are validated one by one when matched.
+func validateBatch(body *dto.BatchQuery) error {
+	var errs validation.Errors
+	if len(body.Requests) == 0 {
+		errs.Add("requests", "must contain at least one query")
+	}
+	if len(body.Requests) > maxBatchSize {
+		errs.Add("requests", "must not contain more than %d queries", maxBatchSize)
+	}
+	seen := make(map[string]bool, len(body.Requests))
+	for i, r := range body.Requests {
+		field := "requests." + strconv.Itoa(i) + ".id"
+		switch {
+		case r.Id == "":
+			errs.Add(field, "must not be empty")
+		case seen[r.Id]:
+			errs.Add(field, "must be unique, %q is used twice", r.Id)
+		}
+		seen[r.Id] = true
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
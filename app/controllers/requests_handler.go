@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"aroundHome/app/leads"
+	"aroundHome/app/matching"
+	"aroundHome/app/models"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"aroundHome/app/validation"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"regexp"
+	"strconv"
+)
+
+var phoneNumber = regexp.MustCompile(`^\+?[0-9][0-9 /-]{4,}[0-9]$`)
+
+// CreateRequestHandler godoc
+// @Summary Store a customer request and match partners for it.
+// @Description Validates and stores the request, matches and ranks partners for it and stores the partners shown, so the lead can be followed up later. The request is then offered to the best ranked partners, see /requests/{id}/leads.
+// @Tags requests
+// @Accept json
+// @Produce json
+// @Param request body models.CustomerRequest true "Customer request with Materials, Lat, Lng, Sqm and Phone"
+// @Success 201 {object} models.CustomerRequest
+// @Router /requests [post]
+func CreateRequestHandler(c *fiber.Ctx, matcher *matching.Service, requests repository.RequestRepository, dispatcher *leads.Dispatcher) error {
+	request := new(models.CustomerRequest)
+	if err := c.BodyParser(request); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	if err := createRequest(c, matcher, requests, dispatcher, request); err != nil {
+		return err
+	}
+	if err := c.Status(fiber.StatusCreated).JSON(request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createRequest validates, matches, stores and dispatches request and points
+// the Location header at it.
+func createRequest(c *fiber.Ctx, matcher *matching.Service, requests repository.RequestRepository, dispatcher *leads.Dispatcher, request *models.CustomerRequest) error {
+	match := matching.Request{Lat: request.Lat, Lng: request.Lng, Materials: request.Materials, Sqm: float64(request.Sqm)}
+	var errs validation.Errors
+	err := matcher.Validate(c.UserContext(), match)
+	if err != nil && !errors.As(err, &errs) {
+		return err
+	}
+	if !phoneNumber.MatchString(request.Phone) {
+		errs.Add("phone", "must be a phone number")
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	request.Matches, err = matcher.Match(c.UserContext(), match)
+	if err != nil {
+		return err
+	}
+	if err := requests.Create(c.UserContext(), request); err != nil {
+		return err
+	}
+	if _, err := dispatcher.Dispatch(c.UserContext(), request.Id); err != nil {
+		return err
+	}
+	c.Location(c.Path() + "/" + strconv.FormatInt(request.Id, 10))
+	return nil
+}
+
+// GetRequestHandler godoc
+// @Summary Get a stored customer request.
+// @Description Returns the request together with the ranked partners that were shown for it.
+// @Tags requests
+// @Accept */*
+// @Produce json
+// @Param id path int true "Request ID"
+// @Success 200 {object} models.CustomerRequest
+// @Router /requests/{id} [get]
+func GetRequestHandler(c *fiber.Ctx, requests repository.RequestRepository) error {
+	request, err := getRequest(c, requests)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getRequest(c *fiber.Ctx, requests repository.RequestRepository) (*models.CustomerRequest, error) {
+	id, err := int64Param(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	return requests.Get(c.UserContext(), id)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/leads"
+	"aroundHome/app/matching"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateRequestV1Handler godoc
+// @Summary Store a customer request and match partners for it.
+// @Description Validates and stores the request, matches and ranks partners for it and stores the partners shown, so the lead can be followed up later. The request is then offered to the best ranked partners, see /v1/requests/{id}/leads.
+// @Tags v1
+// @Accept json
+// @Produce json
+// @Param request body dto.CustomerRequest true "Customer request with materials, lat, lng, sqm and phone"
+// @Success 201 {object} dto.CustomerRequest
+// @Failure 422 {object} problem.Problem
+// @Router /v1/requests [post]
+func CreateRequestV1Handler(c *fiber.Ctx, matcher *matching.Service, requests repository.RequestRepository, dispatcher *leads.Dispatcher) error {
+	body := new(dto.CustomerRequest)
+	if err := c.BodyParser(body); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	request := body.Model()
+	if err := createRequest(c, matcher, requests, dispatcher, request); err != nil {
+		return err
+	}
+	if err := c.Status(fiber.StatusCreated).JSON(dto.FromCustomerRequest(request)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetRequestV1Handler godoc
+// @Summary Get a stored customer request.
+// @Description Returns the request together with the ranked partners that were shown for it.
+// @Tags v1
+// @Accept */*
+// @Produce json
+// @Param id path int true "Request ID"
+// @Success 200 {object} dto.CustomerRequest
+// @Failure 404 {object} problem.Problem
+// @Router /v1/requests/{id} [get]
+func GetRequestV1Handler(c *fiber.Ctx, requests repository.RequestRepository) error {
+	request, err := getRequest(c, requests)
+	if err != nil {
+		return err
+	}
+	if err := c.JSON(dto.FromCustomerRequest(request)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,14 +1,94 @@
 package app
 
 import (
+	"aroundHome/app/logging"
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
-func DatabaseConnect() *sql.DB {
+const (
+	// DefaultConnectWait is how long DatabaseConnect waits for the database
+	// without PG_CONNECT_WAIT.
+	DefaultConnectWait = 30 * time.Second
+	// initialBackoff and maxBackoff bound the pause between attempts of Retry.
+	initialBackoff = 100 * time.Millisecond
+	maxBackoff     = 5 * time.Second
+)
+
+// DatabaseConnect opens the database of DataSourceName and waits until it
+// answers a ping, so the server can be started next to a database that is
+// still starting up. Pings are retried for PG_CONNECT_WAIT (30s), see Retry.
+func DatabaseConnect(ctx context.Context) (*sql.DB, error) {
+	wait := DefaultConnectWait
+	if v := os.Getenv("PG_CONNECT_WAIT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("PG_CONNECT_WAIT: %w", err)
+		}
+		wait = d
+	}
+	dsn, err := DataSourceName()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	attempt := 0
+	err = Retry(ctx, wait, func(ctx context.Context) error {
+		attempt++
+		err := db.PingContext(ctx)
+		if err != nil {
+			logging.Warn(ctx, "database not ready", "attempt", attempt, "error", err)
+		}
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to the database: %w", err)
+	}
+	return db, nil
+}
+
+// Retry calls try until it succeeds, pausing after every failure twice as long
+// as after the previous one, from 100ms up to 5s. It gives up with the last
+// error once maxWait passed or ctx is done; the context given to try ends then
+// as well.
+func Retry(ctx context.Context, maxWait time.Duration, try func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+	backoff := initialBackoff
+	var last error
+	for {
+		err := try(ctx)
+		if err == nil {
+			return nil
+		}
+		// a try cut short by the end of ctx tells less than the failure before
+		if last == nil || ctx.Err() == nil {
+			last = err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// DataSourceName builds the PostgreSQL connection string from the PG_* environment variables.
+func DataSourceName() (string, error) {
 	host := os.Getenv("PG_HOSTNAME")
 	if host == "" {
 		host = "localhost"
@@ -17,9 +97,9 @@ func DatabaseConnect() *sql.DB {
 	if pgPort == "" {
 		pgPort = "5432"
 	}
-	port, err := strconv.ParseInt(pgPort, 10, 16)
+	port, err := strconv.ParseUint(pgPort, 10, 16)
 	if err != nil {
-		log.Fatal(err)
+		return "", fmt.Errorf("PG_PORT must be a port number, got %q", pgPort)
 	}
 	user := os.Getenv("PG_USER")
 	if user == "" {
@@ -33,16 +113,7 @@ func DatabaseConnect() *sql.DB {
 	if dbname == "" {
 		dbname = "aroundhome"
 	}
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+
+	return fmt.Sprintf("host=%s port=%d user=%s "+
 		"password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
-	db, err := sql.Open("postgres", psqlInfo)
-	if err != nil {
-		panic(err)
-	}
-	err = db.Ping()
-	if err != nil {
-		panic(err)
-	}
-	return db
+		host, port, user, password, dbname), nil
 }
@@ -0,0 +1,201 @@
+// Package drain shuts the web and gRPC servers down gracefully. It tracks the
+// requests in flight, so at the shutdown deadline the requests still running
+// can be cancelled and their connections closed, and the servers are only
+// reported stopped once no handler runs anymore.
+package drain
+
+import (
+	"aroundHome/app/logging"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+)
+
+// ErrTimeout is returned by Shutdown when requests were still running at the
+// deadline and had to be dropped.
+var ErrTimeout = errors.New("requests still running after the shutdown timeout were dropped")
+
+// key is the local holding the Tracker of a request for Hold.
+const key = "drain"
+
+// Tracker tracks the requests in flight of a web and a gRPC server.
+type Tracker struct {
+	// ctx is the context of the HTTP requests, cancelled when they are dropped
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	idle    *sync.Cond
+	running int
+	// conns counts the HTTP requests in flight per connection
+	conns map[net.Conn]int
+}
+
+func New() *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &Tracker{ctx: ctx, cancel: cancel, conns: make(map[net.Conn]int)}
+	t.idle = sync.NewCond(&t.mu)
+	return t
+}
+
+// Middleware tracks the HTTP requests. It must be registered first, as it
+// replaces the user context with one cancelled when the request is dropped.
+func (t *Tracker) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conn := c.Context().Conn()
+		t.begin(conn)
+		defer t.end(conn)
+		c.SetUserContext(t.ctx)
+		c.Locals(key, t)
+		return c.Next()
+	}
+}
+
+// Hold keeps the request of c in flight until release is called, for bodies
+// written after the handler returned, like by a body stream writer. Without a
+// Tracker release does nothing.
+func Hold(c *fiber.Ctx) (release func()) {
+	t, ok := c.Locals(key).(*Tracker)
+	if !ok {
+		return func() {}
+	}
+	conn := c.Context().Conn()
+	t.begin(conn)
+	var once sync.Once
+	return func() {
+		once.Do(func() { t.end(conn) })
+	}
+}
+
+// ServerOptions track the gRPC calls. Their contexts are cancelled by
+// grpc.Server.Stop when they are dropped.
+func (t *Tracker) ServerOptions() []grpc.ServerOption {
+	unary := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		t.begin(nil)
+		defer t.end(nil)
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		t.begin(nil)
+		defer t.end(nil)
+		return handler(srv, ss)
+	}
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream)}
+}
+
+func (t *Tracker) begin(conn net.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running++
+	if conn != nil {
+		t.conns[conn]++
+	}
+}
+
+func (t *Tracker) end(conn net.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running--
+	if conn != nil {
+		if t.conns[conn]--; t.conns[conn] == 0 {
+			delete(t.conns, conn)
+		}
+	}
+	if t.running == 0 {
+		t.idle.Broadcast()
+	}
+}
+
+// drop cancels the HTTP requests in flight and closes their connections. It
+// returns the number of requests running.
+func (t *Tracker) drop() int {
+	t.cancel()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for conn := range t.conns {
+		conn.Close()
+	}
+	return t.running
+}
+
+// wait returns once no request is running.
+func (t *Tracker) wait() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.running > 0 {
+		t.idle.Wait()
+	}
+}
+
+// Run serves webApp and grpcServer on their listeners until one of them fails
+// or ctx is done, like by a signal, and shuts both down then.
+func (t *Tracker) Run(ctx context.Context, webApp *fiber.App, webListener net.Listener, grpcServer *grpc.Server, grpcListener net.Listener, timeout time.Duration) error {
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- grpcServer.Serve(grpcListener)
+	}()
+	go func() {
+		serveErr <- webApp.Listener(webListener)
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		logging.Info(context.Background(), "shutting down", "timeout", timeout)
+	}
+	if shutdownErr := t.Shutdown(webApp, grpcServer, timeout); err == nil {
+		err = shutdownErr
+	}
+	// in case the web server had not started serving yet
+	webListener.Close()
+	return err
+}
+
+// Shutdown stops the servers from accepting connections and waits for the
+// requests in flight to finish. Requests still running after timeout are
+// cancelled and their connections closed; Shutdown returns ErrTimeout once
+// their handlers returned, so nothing uses the dependencies of the servers
+// after that. Handlers are expected to return when their context is done.
+func (t *Tracker) Shutdown(webApp *fiber.App, grpcServer *grpc.Server, timeout time.Duration) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	var webErr error
+	go func() {
+		defer wg.Done()
+		// fiber waits for the requests without a deadline, the timer below is one
+		webErr = webApp.Shutdown()
+	}()
+	go func() {
+		defer wg.Done()
+		grpcServer.GracefulStop()
+	}()
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		t.wait()
+		return webErr
+	case <-timer.C:
+	}
+	dropped := t.drop()
+	grpcServer.Stop()
+	<-stopped
+	t.wait()
+	if dropped == 0 {
+		return webErr
+	}
+	return fmt.Errorf("%w: %d after %v", ErrTimeout, dropped, timeout)
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"aroundHome/app/models"
+	"time"
+)
+
+// Lead is a customer request offered to one of its matched partners.
+type Lead struct {
+	Id        int64     `json:"id"`
+	RequestId int64     `json:"request_id"`
+	PartnerId int16     `json:"partner_id"`
+	Rank      int       `json:"rank"`
+	State     string    `json:"state" enums:"offered,viewed,accepted,declined,expired"`
+	OfferedAt time.Time `json:"offered_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func FromLead(l *models.Lead) Lead {
+	return Lead{
+		Id:        l.Id,
+		RequestId: l.RequestId,
+		PartnerId: l.PartnerId,
+		Rank:      l.Rank,
+		State:     string(l.State),
+		OfferedAt: l.OfferedAt,
+		ExpiresAt: l.ExpiresAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+func FromLeads(leads []*models.Lead) []Lead {
+	dto := make([]Lead, len(leads))
+	for i, l := range leads {
+		dto[i] = FromLead(l)
+	}
+	return dto
+}
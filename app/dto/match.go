@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"aroundHome/app/matching"
+	"aroundHome/app/models"
+	"aroundHome/app/problem"
+	"time"
+)
+
+// Kilometers is the unit of distances.
+const Kilometers = "km"
+
+// Distance is a length with its unit.
+type Distance struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit" example:"km"`
+}
+
+// Match is a partner matched for a customer request with its distance to the
+// customer, its score and the contribution of every ranking factor to it.
+type Match struct {
+	Partner
+	Distance Distance           `json:"distance"`
+	Score    float64            `json:"score"`
+	Factors  map[string]float64 `json:"factors,omitempty"`
+}
+
+// MatchResult is the answer to a stateless query.
+type MatchResult struct {
+	Phone   string  `json:"phone,omitempty"`
+	Sqm     float64 `json:"sqm,omitempty"`
+	Matches []Match `json:"matches"`
+}
+
+// BatchQuery is a batch of stateless queries, each with an id chosen by the caller.
+type BatchQuery struct {
+	Requests []BatchRequest `json:"requests"`
+}
+
+// BatchRequest is a query of a batch, its id keys its result.
+type BatchRequest struct {
+	Id        string   `json:"id" example:"lead-1"`
+	Lat       float64  `json:"lat" example:"40.076762"`
+	Lng       float64  `json:"lng" example:"113.300129"`
+	Materials []string `json:"materials" example:"carpet,tiles"`
+	Sqm       float64  `json:"sqm" minimum:"0" example:"35"`
+}
+
+// BatchResult holds the result of every query of a batch by its id.
+type BatchResult struct {
+	Results map[string]BatchItem `json:"results"`
+}
+
+// BatchItem is the result of a query of a batch. Matches is null if the query
+// failed, Error holds the problem details of the failure.
+type BatchItem struct {
+	Matches []Match          `json:"matches"`
+	Error   *problem.Problem `json:"error,omitempty"`
+}
+
+// CustomerRequest is a stored customer request with the matches shown for it.
+// Id, the timestamps and the matches are ignored when creating a request.
+type CustomerRequest struct {
+	Id        int64     `json:"id"`
+	Materials []string  `json:"materials"`
+	Lat       float64   `json:"lat"`
+	Lng       float64   `json:"lng"`
+	Sqm       float64   `json:"sqm"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Matches   []Match   `json:"matches"`
+}
+
+func FromMatch(m *models.PartnerWithDistance) Match {
+	dto := Match{
+		Partner:  FromPartner(&m.Partner),
+		Distance: Distance{Value: float(m.Distance), Unit: Kilometers},
+		Score:    float(m.Score),
+	}
+	if len(m.Factors) > 0 {
+		dto.Factors = make(map[string]float64, len(m.Factors))
+		for name, v := range m.Factors {
+			dto.Factors[name] = float(v)
+		}
+	}
+	return dto
+}
+
+func FromMatches(matches []*models.PartnerWithDistance) []Match {
+	dto := make([]Match, len(matches))
+	for i, m := range matches {
+		dto[i] = FromMatch(m)
+	}
+	return dto
+}
+
+func FromCustomerRequest(r *models.CustomerRequest) CustomerRequest {
+	return CustomerRequest{
+		Id:        r.Id,
+		Materials: r.Materials,
+		Lat:       r.Lat,
+		Lng:       r.Lng,
+		Sqm:       float(r.Sqm),
+		Phone:     r.Phone,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+		Matches:   FromMatches(r.Matches),
+	}
+}
+
+// Request returns the query as matching.Request.
+func (r BatchRequest) Request() matching.Request {
+	return matching.Request{Lat: r.Lat, Lng: r.Lng, Materials: r.Materials, Sqm: r.Sqm}
+}
+
+// FromBatchResult returns the results of the requests by their ids.
+func FromBatchResult(requests []BatchRequest, results []matching.BatchResult) BatchResult {
+	dto := BatchResult{Results: make(map[string]BatchItem, len(results))}
+	for i, result := range results {
+		if result.Err != nil {
+			dto.Results[requests[i].Id] = BatchItem{Error: problem.From(result.Err)}
+		} else {
+			dto.Results[requests[i].Id] = BatchItem{Matches: FromMatches(result.Matches)}
+		}
+	}
+	return dto
+}
+
+// Model returns the request as models.CustomerRequest without id, timestamps and matches.
+func (r CustomerRequest) Model() *models.CustomerRequest {
+	return &models.CustomerRequest{
+		Materials: r.Materials,
+		Lat:       r.Lat,
+		Lng:       r.Lng,
+		Sqm:       float32(r.Sqm),
+		Phone:     r.Phone,
+	}
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "aroundHome/app/models"
+
+// Material is a material of the catalog, Parent is the code of its category.
+type Material struct {
+	Code   string            `json:"code"`
+	Parent string            `json:"parent,omitempty"`
+	Names  map[string]string `json:"names"`
+	Active bool              `json:"active"`
+}
+
+func FromMaterial(m *models.Material) Material {
+	return Material{Code: m.Code, Parent: m.Parent, Names: m.Names, Active: m.Active}
+}
+
+func FromMaterials(materials []*models.Material) []Material {
+	dto := make([]Material, len(materials))
+	for i, m := range materials {
+		dto[i] = FromMaterial(m)
+	}
+	return dto
+}
+
+// Model returns the material as models.Material.
+func (m Material) Model() *models.Material {
+	return &models.Material{Code: m.Code, Parent: m.Parent, Names: m.Names, Active: m.Active}
+}
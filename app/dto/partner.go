@@ -0,0 +1,107 @@
+// Package dto holds the representations of the v1 API. They are decoupled from
+// the models, so the models can change without breaking clients.
+package dto
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/app/validation"
+	"errors"
+	"strconv"
+)
+
+// Partner is a flooring partner.
+type Partner struct {
+	Id   int16   `json:"id"`
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lng  float64 `json:"lng"`
+	// RadiusKm is the operating radius around the office in kilometers.
+	RadiusKm  float64  `json:"radius_km"`
+	Rating    float64  `json:"rating" minimum:"0" maximum:"10"`
+	Materials []string `json:"materials"`
+	// MinSqm and MaxSqm are the project sizes in square meters the partner prefers, 0 for no limit.
+	MinSqm float64 `json:"min_sqm" minimum:"0"`
+	MaxSqm float64 `json:"max_sqm" minimum:"0"`
+}
+
+// PartnerPage is a page of partners, NextCursor is empty on the last page.
+type PartnerPage struct {
+	Partners   []Partner `json:"partners"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+func FromPartner(p *models.Partner) Partner {
+	return Partner{
+		Id:        p.Id,
+		Name:      p.Name,
+		Lat:       float(p.Lat),
+		Lng:       float(p.Lng),
+		RadiusKm:  float(p.Radius),
+		Rating:    float(p.Rating),
+		Materials: p.Materials(),
+		MinSqm:    float(p.MinSqm),
+		MaxSqm:    float(p.MaxSqm),
+	}
+}
+
+func FromPartnerPage(page *repository.PartnerPage) PartnerPage {
+	dto := PartnerPage{Partners: make([]Partner, len(page.Partners))}
+	for i, p := range page.Partners {
+		dto.Partners[i] = FromPartner(p)
+	}
+	if page.Next != nil {
+		dto.NextCursor = page.Next.String()
+	}
+	return dto
+}
+
+// Model returns the partner as models.Partner.
+func (p Partner) Model() *models.Partner {
+	materials := p.Materials
+	if materials == nil {
+		materials = []string{}
+	}
+	return &models.Partner{
+		Id:                 p.Id,
+		Name:               p.Name,
+		Lat:                float32(p.Lat),
+		Lng:                float32(p.Lng),
+		Radius:             float32(p.RadiusKm),
+		Rating:             float32(p.Rating),
+		FlooringExperience: models.MaterialsLiteral(materials),
+		MinSqm:             float32(p.MinSqm),
+		MaxSqm:             float32(p.MaxSqm),
+	}
+}
+
+// partnerFields maps the fields validation.Partner reports to the fields of Partner.
+var partnerFields = map[string]string{
+	"radius":              "radius_km",
+	"flooring_experience": "materials",
+}
+
+// PartnerErrors renames the fields of validation.Errors of a models.Partner to
+// the fields of Partner. Other errors are returned unchanged.
+func PartnerErrors(err error) error {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	renamed := make(validation.Errors, len(errs))
+	for i, e := range errs {
+		if field, ok := partnerFields[e.Field]; ok {
+			e.Field = field
+		}
+		renamed[i] = e
+	}
+	return renamed
+}
+
+// float converts the float32 values of the models to the float64 with the
+// shortest decimal representation of the float32, so a latitude stored as
+// float32(40.076762) is 40.076763 rather than 40.07676315307617.
+func float(f float32) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return v
+}
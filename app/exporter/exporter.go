@@ -0,0 +1,255 @@
+// Package exporter streams partners as CSV, NDJSON or GeoJSON.
+package exporter
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format is the encoding of an export.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+	GeoJSON Format = "geojson"
+)
+
+// ParseFormat returns the Format named by s.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, NDJSON, GeoJSON:
+		return f, nil
+	case "jsonl":
+		return NDJSON, nil
+	}
+	return "", fmt.Errorf("unknown format %q, use csv, ndjson or geojson", s)
+}
+
+// FormatFromPath derives the Format from the extension of a file name.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case GeoJSON:
+		return "application/geo+json"
+	}
+	return "text/csv"
+}
+
+// record is the NDJSON representation of a partner, readable by the importer.
+type record struct {
+	Id                 int16    `json:"id"`
+	Name               string   `json:"name"`
+	Lat                float32  `json:"lat"`
+	Lng                float32  `json:"lng"`
+	Radius             float32  `json:"radius"`
+	Rating             float32  `json:"rating"`
+	FlooringExperience []string `json:"flooring_experience"`
+	MinSqm             float32  `json:"min_sqm,omitempty"`
+	MaxSqm             float32  `json:"max_sqm,omitempty"`
+}
+
+type feature struct {
+	Type       string      `json:"type"`
+	Id         int16       `json:"id"`
+	Geometry   point       `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+type point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float32 `json:"coordinates"`
+}
+
+// properties of a GeoJSON feature, radius is in kilometers.
+type properties struct {
+	Name               string   `json:"name"`
+	Radius             float32  `json:"radius"`
+	Rating             float32  `json:"rating"`
+	FlooringExperience []string `json:"flooring_experience"`
+	MinSqm             float32  `json:"min_sqm,omitempty"`
+	MaxSqm             float32  `json:"max_sqm,omitempty"`
+}
+
+// propertiesV1 of a GeoJSON feature of the v1 API, named like dto.Partner.
+type propertiesV1 struct {
+	Name      string   `json:"name"`
+	RadiusKm  float64  `json:"radius_km"`
+	Rating    float64  `json:"rating"`
+	Materials []string `json:"materials"`
+	MinSqm    float64  `json:"min_sqm,omitempty"`
+	MaxSqm    float64  `json:"max_sqm,omitempty"`
+}
+
+// layout names the fields of a partner in an export.
+type layout struct {
+	header     []string
+	record     func(*models.Partner) interface{}
+	properties func(*models.Partner) interface{}
+}
+
+// unversioned names the fields like the columns of the partners table.
+var unversioned = layout{
+	header: []string{"id", "name", "lat", "lng", "radius", "rating", "flooring_experience", "min_sqm", "max_sqm"},
+	record: func(p *models.Partner) interface{} {
+		return record{
+			Id:                 p.Id,
+			Name:               p.Name,
+			Lat:                p.Lat,
+			Lng:                p.Lng,
+			Radius:             p.Radius,
+			Rating:             p.Rating,
+			FlooringExperience: p.Materials(),
+			MinSqm:             p.MinSqm,
+			MaxSqm:             p.MaxSqm,
+		}
+	},
+	properties: func(p *models.Partner) interface{} {
+		return properties{
+			Name:               p.Name,
+			Radius:             p.Radius,
+			Rating:             p.Rating,
+			FlooringExperience: p.Materials(),
+			MinSqm:             p.MinSqm,
+			MaxSqm:             p.MaxSqm,
+		}
+	},
+}
+
+// v1 names the fields like dto.Partner, with radius_km and materials.
+var v1 = layout{
+	header: []string{"id", "name", "lat", "lng", "radius_km", "rating", "materials", "min_sqm", "max_sqm"},
+	record: func(p *models.Partner) interface{} {
+		return dto.FromPartner(p)
+	},
+	properties: func(p *models.Partner) interface{} {
+		partner := dto.FromPartner(p)
+		return propertiesV1{
+			Name:      partner.Name,
+			RadiusKm:  partner.RadiusKm,
+			Rating:    partner.Rating,
+			Materials: partner.Materials,
+			MinSqm:    partner.MinSqm,
+			MaxSqm:    partner.MaxSqm,
+		}
+	},
+}
+
+// Write streams every partner to w in the given format, one partner at a time.
+// The fields are named like the columns of the partners table, which the
+// importer reads back.
+func Write(ctx context.Context, w io.Writer, partners repository.PartnerRepository, format Format) error {
+	return write(ctx, w, partners, format, unversioned)
+}
+
+// WriteV1 streams every partner to w like Write, with the fields named like
+// dto.Partner of the v1 API, which importer.ReadV1 reads back.
+func WriteV1(ctx context.Context, w io.Writer, partners repository.PartnerRepository, format Format) error {
+	return write(ctx, w, partners, format, v1)
+}
+
+func write(ctx context.Context, w io.Writer, partners repository.PartnerRepository, format Format, l layout) error {
+	switch format {
+	case CSV:
+		return writeCSV(ctx, w, partners, l)
+	case NDJSON:
+		return writeNDJSON(ctx, w, partners, l)
+	case GeoJSON:
+		return writeGeoJSON(ctx, w, partners, l)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func writeCSV(ctx context.Context, w io.Writer, partners repository.PartnerRepository, l layout) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(l.header); err != nil {
+		return err
+	}
+	err := partners.Each(ctx, func(p *models.Partner) error {
+		materials := p.Materials()
+		for i, v := range materials {
+			materials[i] = "'" + v + "'"
+		}
+		writer.Write([]string{
+			strconv.Itoa(int(p.Id)),
+			p.Name,
+			formatFloat(p.Lat),
+			formatFloat(p.Lng),
+			formatFloat(p.Radius),
+			formatFloat(p.Rating),
+			"[" + strings.Join(materials, ",") + "]",
+			formatSqm(p.MinSqm),
+			formatSqm(p.MaxSqm),
+		})
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeNDJSON(ctx context.Context, w io.Writer, partners repository.PartnerRepository, l layout) error {
+	encoder := json.NewEncoder(w)
+	return partners.Each(ctx, func(p *models.Partner) error {
+		return encoder.Encode(l.record(p))
+	})
+}
+
+func writeGeoJSON(ctx context.Context, w io.Writer, partners repository.PartnerRepository, l layout) error {
+	if _, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`); err != nil {
+		return err
+	}
+	separator := "\n"
+	err := partners.Each(ctx, func(p *models.Partner) error {
+		f, err := json.Marshal(feature{
+			Type: "Feature",
+			Id:   p.Id,
+			// GeoJSON positions are longitude first
+			Geometry:   point{Type: "Point", Coordinates: [2]float32{p.Lng, p.Lat}},
+			Properties: l.properties(p),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ",\n"
+		_, err = w.Write(f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n]}\n")
+	return err
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+// formatSqm leaves project size limits empty when there is none.
+func formatSqm(sqm float32) string {
+	if sqm == 0 {
+		return ""
+	}
+	return formatFloat(sqm)
+}
@@ -0,0 +1,66 @@
+package geo
+
+import "math"
+
+// Box is a latitude/longitude rectangle in degrees. When it crosses the
+// antimeridian MinLng is greater than MaxLng.
+type Box struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// BoundingBox returns the smallest Box containing every point within radius
+// kilometers of the given point. Boxes reaching a pole span all longitudes.
+func BoundingBox(lat, lng, radius float64) Box {
+	angular := radius / EarthRadius
+	box := Box{
+		MinLat: lat - degrees(angular),
+		MaxLat: lat + degrees(angular),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+	deltaLng := degrees(math.Asin(math.Sin(angular) / math.Cos(radians(lat))))
+	if math.IsNaN(deltaLng) || deltaLng >= 180 {
+		return box
+	}
+	box.MinLng = wrapLng(lng - deltaLng)
+	box.MaxLng = wrapLng(lng + deltaLng)
+	return box
+}
+
+// CrossesAntimeridian reports whether the box wraps around longitude 180.
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// Contains reports whether the point lies within the box, borders included.
+func (b Box) Contains(lat, lng float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return lng >= b.MinLng || lng <= b.MaxLng
+	}
+	return lng >= b.MinLng && lng <= b.MaxLng
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+func wrapLng(lng float64) float64 {
+	if lng < -180 {
+		return lng + 360
+	}
+	if lng > 180 {
+		return lng - 360
+	}
+	return lng
+}
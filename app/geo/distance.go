@@ -0,0 +1,21 @@
+package geo
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in kilometers.
+const EarthRadius = 6371.0
+
+// Distance returns the great-circle distance in kilometers between two points
+// given in degrees. It uses the same haversine formula as the distance_km
+// database function so both paths rank partners identically.
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	sinLat := math.Sin(radians(lat2-lat1) / 2)
+	sinLng := math.Sin(radians(lng2-lng1) / 2)
+	a := sinLat*sinLat + math.Cos(radians(lat1))*math.Cos(radians(lat2))*sinLng*sinLng
+	// rounding can push a slightly above 1 for antipodal points
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
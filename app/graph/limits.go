@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// DefaultMaxDepth is the deepest nesting of fields a query may select.
+	DefaultMaxDepth = 6
+	// DefaultMaxComplexity is the highest estimated number of fields a query may resolve.
+	DefaultMaxComplexity = 2000
+	// matchEstimate is the number of matches assumed when estimating the complexity of match.
+	matchEstimate = 20
+)
+
+// Limits bound the cost of a query before it is executed. Introspection
+// fields are not counted, their nesting is bounded by the schema.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// check returns an error if the operation selects fields deeper than the
+// limit or if resolving it is estimated to be too complex.
+func (l Limits) check(doc *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) error {
+	c := &cost{fragments: make(map[string]*ast.FragmentDefinition), variables: variables}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			c.fragments[f.Name.Value] = f
+		}
+	}
+	depth, complexity := c.selections(operation.SelectionSet, make(map[string]bool))
+	if depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+	}
+	if complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
+
+type cost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selections returns the depth and the complexity of a selection set. Every
+// field costs one plus its selections, times the number of list items it returns.
+func (c *cost) selections(set *ast.SelectionSet, visiting map[string]bool) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, n int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			d, n = c.selections(s.SelectionSet, visiting)
+			d++
+			n = (n + 1) * c.items(s)
+		case *ast.InlineFragment:
+			d, n = c.selections(s.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			// cycles are rejected by validation, this only guards the recursion
+			name := s.Name.Value
+			f := c.fragments[name]
+			if f == nil || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, n = c.selections(f.SelectionSet, visiting)
+			delete(visiting, name)
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += n
+	}
+	return depth, complexity
+}
+
+// items estimates the number of list items a field returns.
+func (c *cost) items(field *ast.Field) int {
+	switch field.Name.Value {
+	case "partners":
+		if n, ok := c.intArgument(field, "first"); ok && n > 0 {
+			// larger pages are capped by the resolver
+			if n > maxFirst {
+				n = maxFirst
+			}
+			return n
+		}
+		return defaultFirst
+	case "match":
+		return matchEstimate
+	}
+	return 1
+}
+
+func (c *cost) intArgument(field *ast.Field, name string) (int, bool) {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			n, err := strconv.Atoi(v.Value)
+			return n, err == nil
+		case *ast.Variable:
+			n, ok := c.variables[v.Name.Value].(float64)
+			if !ok {
+				i, ok := c.variables[v.Name.Value].(int)
+				return i, ok
+			}
+			return int(n), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"sync"
+)
+
+// partnerLoader batches the partner lookups of one request. Resolvers ask for
+// a partner with load and receive a thunk; the executor calls the thunks of a
+// level only after all resolvers of that level ran, so the first thunk called
+// fetches every requested partner with a single Find.
+type partnerLoader struct {
+	partners repository.PartnerRepository
+
+	mu      sync.Mutex
+	pending []int16
+	loaded  map[int16]*models.Partner
+}
+
+func newPartnerLoader(partners repository.PartnerRepository) *partnerLoader {
+	return &partnerLoader{partners: partners, loaded: make(map[int16]*models.Partner)}
+}
+
+// load returns a thunk resolving to the partner with the id, nil if there is none.
+func (l *partnerLoader) load(ctx context.Context, id int16) func() (*models.Partner, error) {
+	l.mu.Lock()
+	if _, ok := l.loaded[id]; !ok {
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (*models.Partner, error) {
+		if err := l.fetch(ctx); err != nil {
+			return nil, err
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.loaded[id], nil
+	}
+}
+
+// fetch loads the pending partners. Ids without a partner are remembered as nil.
+func (l *partnerLoader) fetch(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending) == 0 {
+		return nil
+	}
+	ids := l.pending
+	l.pending = nil
+	page, err := l.partners.Find(ctx, repository.PartnerFilter{Ids: ids, Sort: repository.SortId, Limit: len(ids)})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		l.loaded[id] = nil
+	}
+	for _, p := range page.Partners {
+		l.loaded[p.Id] = p
+	}
+	return nil
+}
+
+type loaderKey struct{}
+
+func withLoader(ctx context.Context, l *partnerLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+func loaderFrom(ctx context.Context) *partnerLoader {
+	return ctx.Value(loaderKey{}).(*partnerLoader)
+}
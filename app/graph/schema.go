@@ -0,0 +1,470 @@
+// Package graph serves partner lookups and matching as GraphQL, so clients can
+// select the fields they need in one round trip. It uses the same repository
+// and matching.Service as the HTTP handlers.
+package graph
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/logging"
+	"aroundHome/app/matching"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	// defaultFirst is the page size of partners without first.
+	defaultFirst = 50
+	// maxFirst caps the page size of partners.
+	maxFirst = 200
+)
+
+// Request is a GraphQL request as posted by clients.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName" extensions:"x-nullable"`
+	Variables     map[string]interface{} `json:"variables" extensions:"x-nullable"`
+}
+
+// Response is the result of a request. Data is null if the request could not
+// be executed, partial if some fields failed.
+type Response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []ResponseError        `json:"errors,omitempty"`
+}
+
+// ResponseError is an error of a request, Path leads to the failed field.
+type ResponseError struct {
+	Message    string                 `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Location is the position of an error in the query.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Schema executes requests against the partners and the matcher.
+type Schema struct {
+	Limits   Limits
+	schema   graphql.Schema
+	partners repository.PartnerRepository
+	matcher  *matching.Service
+}
+
+// New returns the schema with the default limits. It panics if the schema
+// cannot be built, which is a programming error.
+func New(partners repository.PartnerRepository, matcher *matching.Service) *Schema {
+	s := &Schema{
+		Limits:   Limits{MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity},
+		partners: partners,
+		matcher:  matcher,
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: s.query()})
+	if err != nil {
+		panic(err)
+	}
+	s.schema = schema
+	return s
+}
+
+// Execute parses, validates, checks the limits of and executes the request.
+// Errors are reported in the response like GraphQL expects.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return response(nil, gqlerrors.FormatErrors(err))
+	}
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return response(nil, validation.Errors)
+	}
+	// an unknown operation is reported by Execute
+	if operation := findOperation(doc, req.OperationName); operation != nil {
+		if err := s.Limits.check(doc, operation, req.Variables); err != nil {
+			return response(nil, gqlerrors.FormatErrors(err))
+		}
+	}
+	res := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoader(ctx, newPartnerLoader(s.partners)),
+	})
+	data, _ := res.Data.(map[string]interface{})
+	return response(data, res.Errors)
+}
+
+func response(data map[string]interface{}, errs []gqlerrors.FormattedError) *Response {
+	res := &Response{Data: data}
+	for _, err := range errs {
+		e := ResponseError{Message: err.Message, Path: err.Path, Extensions: err.Extensions}
+		for _, l := range err.Locations {
+			e.Locations = append(e.Locations, Location{Line: l.Line, Column: l.Column})
+		}
+		res.Errors = append(res.Errors, e)
+	}
+	return res
+}
+
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" && found != nil {
+			return nil
+		}
+		if name == "" || (operation.Name != nil && operation.Name.Value == name) {
+			found = operation
+		}
+	}
+	return found
+}
+
+func (s *Schema) query() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"partner": &graphql.Field{
+				Type:        partnerType,
+				Description: "The partner with the id, null if there is none.",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: s.resolvePartner,
+			},
+			"partners": &graphql.Field{
+				Type:        graphql.NewNonNull(partnerConnectionType),
+				Description: "A page of partners passing the filter. Pass pageInfo.endCursor as after, with the same filter, to get the next page.",
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: partnerFilterType},
+					"first":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultFirst, Description: "Page size, at most 200."},
+					"after":  &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: s.resolvePartners,
+			},
+			"match": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(matchType))),
+				Description: "The partners covering the location and experienced with every material, best match first.",
+				Args: graphql.FieldConfigArgument{
+					"location":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(locationType)},
+					"materials": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+					"sqm":       &graphql.ArgumentConfig{Type: graphql.Float, Description: "Size of the floor in square meters."},
+				},
+				Resolve: s.resolveMatch,
+			},
+		},
+	})
+}
+
+func (s *Schema) resolvePartner(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(int)
+	if id < 0 || id > math.MaxInt16 {
+		return nil, nil
+	}
+	load := loaderFrom(p.Context).load(p.Context, int16(id))
+	return func() (interface{}, error) {
+		rec, err := load()
+		if err != nil || rec == nil {
+			return nil, resolverError(p.Context, err)
+		}
+		return dto.FromPartner(rec), nil
+	}, nil
+}
+
+func (s *Schema) resolvePartners(p graphql.ResolveParams) (interface{}, error) {
+	filter, err := partnerFilter(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.partners.Find(p.Context, *filter)
+	if err != nil {
+		return nil, resolverError(p.Context, err)
+	}
+	conn := partnerConnection{Nodes: make([]dto.Partner, len(page.Partners))}
+	for i, rec := range page.Partners {
+		conn.Nodes[i] = dto.FromPartner(rec)
+	}
+	if page.Next != nil {
+		conn.PageInfo = pageInfo{EndCursor: page.Next.String(), HasNextPage: true}
+	}
+	return conn, nil
+}
+
+func (s *Schema) resolveMatch(p graphql.ResolveParams) (interface{}, error) {
+	location := p.Args["location"].(map[string]interface{})
+	request := matching.Request{
+		Lat:       location["lat"].(float64),
+		Lng:       location["lng"].(float64),
+		Materials: stringList(p.Args["materials"]),
+	}
+	if sqm, ok := p.Args["sqm"].(float64); ok {
+		request.Sqm = sqm
+	}
+	recs, err := s.matcher.Match(p.Context, request)
+	if err != nil {
+		return nil, resolverError(p.Context, err)
+	}
+	return dto.FromMatches(recs), nil
+}
+
+// partnerFilter reads the arguments of partners.
+func partnerFilter(args map[string]interface{}) (*repository.PartnerFilter, error) {
+	filter := &repository.PartnerFilter{Sort: repository.SortId, Limit: defaultFirst}
+	if first, ok := args["first"].(int); ok {
+		if first < 1 {
+			return nil, userError("first must be positive")
+		}
+		if first > maxFirst {
+			first = maxFirst
+		}
+		filter.Limit = first
+	}
+	if f, ok := args["filter"].(map[string]interface{}); ok {
+		ids, _ := f["ids"].([]interface{})
+		if len(ids) > maxFirst {
+			return nil, userError("filter.ids must not hold more than 200 ids")
+		}
+		for _, v := range ids {
+			// ids out of range cannot exist, -1 matches no partner either
+			id := v.(int)
+			if id < 0 || id > math.MaxInt16 {
+				id = -1
+			}
+			filter.Ids = append(filter.Ids, int16(id))
+		}
+		filter.Materials = stringList(f["materials"])
+		filter.AnyMaterials = stringList(f["anyMaterials"])
+		if v, ok := f["minRating"].(float64); ok {
+			r := float32(v)
+			filter.MinRating = &r
+		}
+		if v, ok := f["maxRating"].(float64); ok {
+			r := float32(v)
+			filter.MaxRating = &r
+		}
+		if v, ok := f["namePrefix"].(string); ok {
+			filter.NamePrefix = v
+		}
+		if v, ok := f["sort"].(string); ok {
+			filter.Sort = repository.Sort(v)
+		}
+	}
+	if after, ok := args["after"].(string); ok {
+		cursor, err := repository.ParseCursor(after, filter.Sort)
+		if err != nil {
+			return nil, userError("after must be the endCursor of a page with the same sort")
+		}
+		filter.After = cursor
+	}
+	return filter, nil
+}
+
+func stringList(v interface{}) []string {
+	values, _ := v.([]interface{})
+	if values == nil {
+		return nil
+	}
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.(string)
+	}
+	return s
+}
+
+// Error is a resolver error with the code clients branch on in its extensions.
+type Error struct {
+	Message string
+	Code    string
+	// Fields are the field errors of invalid input.
+	Fields interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.Code}
+	if e.Fields != nil {
+		ext["errors"] = e.Fields
+	}
+	return ext
+}
+
+func userError(message string) *Error {
+	return &Error{Message: message, Code: "BAD_USER_INPUT"}
+}
+
+// resolverError classifies err like the HTTP API does. Errors not caused by
+// the request are logged and reported without details.
+func resolverError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var graphErr *Error
+	if errors.As(err, &graphErr) {
+		return graphErr
+	}
+	p := problem.From(err)
+	code, ok := p.Code()
+	if !ok {
+		logging.Error(ctx, "graphql resolver failed", "error", err)
+		return &Error{Message: "internal error", Code: code.GraphQL}
+	}
+	e := &Error{Message: p.Detail, Code: code.GraphQL}
+	if len(p.Errors) > 0 {
+		e.Fields = p.Errors
+	}
+	return e
+}
+
+type pageInfo struct {
+	EndCursor   string `json:"endCursor"`
+	HasNextPage bool   `json:"hasNextPage"`
+}
+
+type partnerConnection struct {
+	Nodes    []dto.Partner `json:"nodes"`
+	PageInfo pageInfo      `json:"pageInfo"`
+}
+
+// partnerField resolves a field of a dto.Partner.
+func partnerField(t graphql.Output, description string, fn func(dto.Partner) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(t),
+		Description: description,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return fn(p.Source.(dto.Partner)), nil
+		},
+	}
+}
+
+var partnerType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Partner",
+	Description: "A flooring partner.",
+	Fields: graphql.Fields{
+		"id":   partnerField(graphql.Int, "", func(p dto.Partner) interface{} { return p.Id }),
+		"name": partnerField(graphql.String, "", func(p dto.Partner) interface{} { return p.Name }),
+		"lat":  partnerField(graphql.Float, "", func(p dto.Partner) interface{} { return p.Lat }),
+		"lng":  partnerField(graphql.Float, "", func(p dto.Partner) interface{} { return p.Lng }),
+		"radiusKm": partnerField(graphql.Float, "Operating radius around the office in kilometers.",
+			func(p dto.Partner) interface{} { return p.RadiusKm }),
+		"rating": partnerField(graphql.Float, "", func(p dto.Partner) interface{} { return p.Rating }),
+		"materials": partnerField(graphql.NewList(graphql.NewNonNull(graphql.String)), "Material codes of the catalog.",
+			func(p dto.Partner) interface{} { return p.Materials }),
+		"minSqm": partnerField(graphql.Float, "Smallest preferred project in square meters, 0 for no limit.",
+			func(p dto.Partner) interface{} { return p.MinSqm }),
+		"maxSqm": partnerField(graphql.Float, "Largest preferred project in square meters, 0 for no limit.",
+			func(p dto.Partner) interface{} { return p.MaxSqm }),
+	},
+})
+
+var partnerConnectionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PartnerConnection",
+	Fields: graphql.Fields{
+		"nodes": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(partnerType)))},
+		"pageInfo": &graphql.Field{Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+			Name: "PageInfo",
+			Fields: graphql.Fields{
+				"endCursor":   &graphql.Field{Type: graphql.String, Description: "Cursor of the next page, null on the last page."},
+				"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			},
+		}))},
+	},
+})
+
+var partnerSortType = graphql.NewEnum(graphql.EnumConfig{
+	Name: "PartnerSort",
+	Values: graphql.EnumValueConfigMap{
+		"ID":          &graphql.EnumValueConfig{Value: string(repository.SortId)},
+		"NAME":        &graphql.EnumValueConfig{Value: string(repository.SortName)},
+		"RATING":      &graphql.EnumValueConfig{Value: string(repository.SortRating)},
+		"RATING_DESC": &graphql.EnumValueConfig{Value: string(repository.SortRatingDesc)},
+	},
+})
+
+var partnerFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PartnerFilter",
+	Description: "Filters of partners, empty fields do not filter.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"ids":          &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.Int))},
+		"materials":    &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Partners experienced with all of these materials."},
+		"anyMaterials": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Partners experienced with any of these materials."},
+		"minRating":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"maxRating":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"namePrefix":   &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Start of the name, ignoring case."},
+		"sort":         &graphql.InputObjectFieldConfig{Type: partnerSortType, DefaultValue: string(repository.SortId)},
+	},
+})
+
+var locationType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "Location",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"lat": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"lng": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+type factor struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+var matchType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Match",
+	Description: "A partner matched for a customer with its distance and score.",
+	Fields: graphql.Fields{
+		"partner": &graphql.Field{
+			Type: graphql.NewNonNull(partnerType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(dto.Match).Partner, nil
+			},
+		},
+		"distance": &graphql.Field{Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+			Name: "Distance",
+			Fields: graphql.Fields{
+				"value": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+				"unit":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			},
+		}))},
+		"score": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"factors": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(factorType))),
+			Description: "Contribution of every ranking factor to the score.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				m := p.Source.(dto.Match)
+				factors := make([]factor, 0, len(m.Factors))
+				for name, v := range m.Factors {
+					factors = append(factors, factor{Name: name, Value: v})
+				}
+				sort.Slice(factors, func(i, j int) bool { return factors[i].Name < factors[j].Name })
+				return factors, nil
+			},
+		},
+	},
+})
+
+var factorType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Factor",
+	Fields: graphql.Fields{
+		"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"value": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
@@ -0,0 +1,110 @@
+// Package health decides whether the server is ready to answer requests by
+// checking the dependencies it needs, like the database.
+package health
+
+import (
+	"aroundHome/app/spatial"
+	"aroundHome/db/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds every check run by the readiness probe.
+const DefaultTimeout = 2 * time.Second
+
+// Statuses of a Report and of its results.
+const (
+	StatusOk     = "ok"
+	StatusFailed = "failed"
+)
+
+// Check is a dependency of the server. Run fails with the reason the
+// dependency cannot be used.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Status    string  `json:"status" example:"ok"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms" example:"0.42"`
+}
+
+// Report holds the results of all checks by name. Its status is failed as
+// soon as one check failed.
+type Report struct {
+	Status string            `json:"status" example:"ok"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether all checks passed.
+func (r *Report) Ready() bool {
+	return r.Status == StatusOk
+}
+
+// Run runs checks concurrently, each bounded by timeout, so a hanging
+// dependency cannot hold the probe longer than the slowest timeout.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) *Report {
+	report := &Report{Status: StatusOk, Checks: make(map[string]Result, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			err := check.Run(ctx)
+			result := Result{Status: StatusOk, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				result.Status = StatusFailed
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if err != nil {
+				report.Status = StatusFailed
+			}
+		}(check)
+	}
+	wg.Wait()
+	return report
+}
+
+// Database checks that db answers a ping.
+func Database(db *sql.DB) Check {
+	return Check{Name: "database", Run: db.PingContext}
+}
+
+// Schema checks that the database schema is not older than the binary
+// expects, like serve does on startup.
+func Schema(migrator *migrations.Migrator) Check {
+	return Check{Name: "schema", Run: migrator.Check}
+}
+
+// Index checks that the in-memory partner index is loaded and was refreshed
+// within maxAge, so an index missing the changes made by other instances for
+// too long fails the check.
+func Index(indexed *spatial.Indexed, maxAge time.Duration) Check {
+	return Check{Name: "index", Run: func(context.Context) error {
+		refreshed := indexed.Refreshed()
+		if refreshed.IsZero() {
+			return errors.New("partner index is not loaded")
+		}
+		if time.Since(refreshed) > maxAge {
+			return fmt.Errorf("partner index was last refreshed at %s, more than %v ago", refreshed.UTC().Format(time.RFC3339), maxAge)
+		}
+		return nil
+	}}
+}
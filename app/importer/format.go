@@ -0,0 +1,33 @@
+// Package importer reads partners from CSV, JSON and NDJSON files and upserts them in batches.
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the encoding of a partner file.
+type Format string
+
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+)
+
+// ParseFormat returns the Format named by s.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSON, NDJSON:
+		return f, nil
+	case "jsonl":
+		return NDJSON, nil
+	}
+	return "", fmt.Errorf("unknown format %q, use csv, json or ndjson", s)
+}
+
+// FormatFromPath derives the Format from the extension of a file name.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"aroundHome/app/validation"
+	"context"
+	"errors"
+)
+
+// DefaultBatchSize is the number of partners upserted per statement.
+const DefaultBatchSize = 500
+
+// Options control how rows are imported.
+type Options struct {
+	// DryRun validates all rows without writing anything.
+	DryRun bool
+	// BatchSize is the number of partners upserted per statement, DefaultBatchSize if 0.
+	BatchSize int
+}
+
+// RowError lists the problems of a single rejected row.
+type RowError struct {
+	Row    int               `json:"row"`
+	Id     int16             `json:"id,omitempty"`
+	Errors validation.Errors `json:"errors"`
+}
+
+// Report summarizes an import.
+type Report struct {
+	DryRun   bool       `json:"dry_run"`
+	Valid    int        `json:"valid"`
+	Imported int        `json:"imported"`
+	Failed   []RowError `json:"failed"`
+}
+
+// Import validates rows against the catalog and upserts the valid ones in batches inside a single
+// transaction. Rows with errors are skipped and listed in the report. An error
+// is returned if writing a batch fails; no partner is imported then.
+func Import(ctx context.Context, partners repository.PartnerRepository, catalog *taxonomy.Catalog, rows []Row, opts Options) (*Report, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	report := &Report{DryRun: opts.DryRun, Failed: make([]RowError, 0)}
+	valid := make([]*models.Partner, 0, len(rows))
+	for _, row := range rows {
+		errs := row.Errors
+		if row.Partner != nil {
+			errs = append(errs, check(row.Partner, catalog)...)
+		}
+		if len(errs) > 0 {
+			rowErr := RowError{Row: row.Number, Errors: errs}
+			if row.Partner != nil {
+				rowErr.Id = row.Partner.Id
+			}
+			report.Failed = append(report.Failed, rowErr)
+			continue
+		}
+		valid = append(valid, row.Partner)
+	}
+	report.Valid = len(valid)
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if err := partners.UpsertBatches(ctx, opts.BatchSize, valid...); err != nil {
+		return report, err
+	}
+	report.Imported = len(valid)
+	return report, nil
+}
+
+func check(p *models.Partner, catalog *taxonomy.Catalog) validation.Errors {
+	var errs validation.Errors
+	if p.Id <= 0 {
+		errs.Add("id", "must be a positive integer")
+	}
+	if err := validation.Partner(p, catalog); err != nil {
+		var fieldErrs validation.Errors
+		if !errors.As(err, &fieldErrs) {
+			fieldErrs.Add("", "%v", err)
+		}
+		errs = append(errs, fieldErrs...)
+	}
+	return errs
+}
@@ -0,0 +1,276 @@
+package importer
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/models"
+	"aroundHome/app/validation"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Row is a single input record, numbered from 1 without the CSV header.
+// Errors holds the problems found while parsing it; Partner is nil if there are any.
+type Row struct {
+	Number  int
+	Partner *models.Partner
+	Errors  validation.Errors
+}
+
+// record is the JSON representation of a partner, using the same names as the CSV header.
+type record struct {
+	Id                 int16           `json:"id"`
+	Name               string          `json:"name"`
+	Lat                float32         `json:"lat"`
+	Lng                float32         `json:"lng"`
+	Radius             float32         `json:"radius"`
+	Rating             float32         `json:"rating"`
+	FlooringExperience json.RawMessage `json:"flooring_experience"`
+	MinSqm             float32         `json:"min_sqm"`
+	MaxSqm             float32         `json:"max_sqm"`
+}
+
+// layout names the fields of a partner in the input.
+type layout struct {
+	radius, materials string
+	decode            func(number int, raw []byte) Row
+}
+
+// unversioned names the fields like the columns of the partners table.
+var unversioned = layout{radius: "radius", materials: "flooring_experience", decode: decodeRecord}
+
+// v1 names the fields like dto.Partner, with radius_km and materials.
+var v1 = layout{radius: "radius_km", materials: "materials", decode: decodePartnerV1}
+
+// columns are required in CSV input, min_sqm and max_sqm may be left out.
+func (l layout) columns() []string {
+	return []string{"id", "name", "lat", "lng", l.radius, "rating", l.materials}
+}
+
+// Read parses all rows of r. An error is only returned when the input as a whole
+// cannot be read; problems with single rows are reported in Row.Errors.
+func Read(r io.Reader, format Format) ([]Row, error) {
+	return read(r, format, unversioned)
+}
+
+// ReadV1 parses all rows of r like Read, with the fields named like
+// dto.Partner of the v1 API. Materials must be given as a list in JSON.
+func ReadV1(r io.Reader, format Format) ([]Row, error) {
+	return read(r, format, v1)
+}
+
+func read(r io.Reader, format Format, l layout) ([]Row, error) {
+	switch format {
+	case CSV:
+		return readCSV(r, l)
+	case JSON:
+		return readJSON(r, l)
+	case NDJSON:
+		return readNDJSON(r, l)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+func readCSV(r io.Reader, l layout) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	index := make(map[string]int)
+	for i, v := range header {
+		index[strings.ToLower(strings.TrimSpace(v))] = i
+	}
+	for _, c := range l.columns() {
+		if _, ok := index[c]; !ok {
+			return nil, fmt.Errorf("CSV header is missing column %q", c)
+		}
+	}
+
+	rows := make([]Row, 0)
+	for number := 1; ; number++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		row := Row{Number: number}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			row.Errors.Add("", "%v", parseErr.Err)
+			rows = append(rows, row)
+			continue
+		}
+		value := func(column string) string {
+			if i, ok := index[column]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+		p := &models.Partner{Name: value("name")}
+		p.Id = parseId(&row.Errors, value("id"))
+		p.Lat = parseFloat(&row.Errors, "lat", value("lat"))
+		p.Lng = parseFloat(&row.Errors, "lng", value("lng"))
+		p.Radius = parseFloat(&row.Errors, l.radius, value(l.radius))
+		p.Rating = parseFloat(&row.Errors, "rating", value("rating"))
+		// project size limits are optional
+		if v := value("min_sqm"); v != "" {
+			p.MinSqm = parseFloat(&row.Errors, "min_sqm", v)
+		}
+		if v := value("max_sqm"); v != "" {
+			p.MaxSqm = parseFloat(&row.Errors, "max_sqm", v)
+		}
+		materials, err := ParseMaterials(value(l.materials))
+		if err != nil {
+			row.Errors.Add(l.materials, "%v", err)
+		}
+		p.FlooringExperience = models.MaterialsLiteral(materials)
+		if len(row.Errors) == 0 {
+			row.Partner = p
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseId(errs *validation.Errors, value string) int16 {
+	id, err := strconv.ParseInt(value, 10, 16)
+	if err != nil {
+		errs.Add("id", "%q is not an integer", value)
+	}
+	return int16(id)
+}
+
+func parseFloat(errs *validation.Errors, field, value string) float32 {
+	n, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		errs.Add(field, "%q is not a number", value)
+	}
+	return float32(n)
+}
+
+func readJSON(r io.Reader, l layout) ([]Row, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("JSON input must be an array of partners")
+	}
+	rows := make([]Row, 0)
+	for number := 1; decoder.More(); number++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("reading JSON element %d: %w", number, err)
+		}
+		rows = append(rows, l.decode(number, raw))
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("reading end of JSON array: %w", err)
+	}
+	return rows, nil
+}
+
+func readNDJSON(r io.Reader, l layout) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	rows := make([]Row, 0)
+	for number := 1; scanner.Scan(); {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rows = append(rows, l.decode(number, line))
+		number++
+	}
+	return rows, scanner.Err()
+}
+
+func decodeRecord(number int, raw []byte) Row {
+	row := Row{Number: number}
+	var rec record
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		row.Errors = decodeErrors(err)
+		return row
+	}
+	var materials []string
+	if err := json.Unmarshal(rec.FlooringExperience, &materials); err != nil {
+		var literal string
+		if json.Unmarshal(rec.FlooringExperience, &literal) != nil {
+			row.Errors.Add("flooring_experience", "must be a list of materials")
+			return row
+		}
+		if materials, err = ParseMaterials(literal); err != nil {
+			row.Errors.Add("flooring_experience", "%v", err)
+			return row
+		}
+	}
+	row.Partner = &models.Partner{
+		Id:                 rec.Id,
+		Name:               strings.TrimSpace(rec.Name),
+		Lat:                rec.Lat,
+		Lng:                rec.Lng,
+		Radius:             rec.Radius,
+		Rating:             rec.Rating,
+		FlooringExperience: models.MaterialsLiteral(materials),
+		MinSqm:             rec.MinSqm,
+		MaxSqm:             rec.MaxSqm,
+	}
+	return row
+}
+
+func decodePartnerV1(number int, raw []byte) Row {
+	row := Row{Number: number}
+	var partner dto.Partner
+	if err := json.Unmarshal(raw, &partner); err != nil {
+		row.Errors = decodeErrors(err)
+		return row
+	}
+	partner.Name = strings.TrimSpace(partner.Name)
+	row.Partner = partner.Model()
+	return row
+}
+
+// decodeErrors reports the field of a value of the wrong type.
+func decodeErrors(err error) validation.Errors {
+	var errs validation.Errors
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		errs.Add(typeErr.Field, "must be a %s", typeErr.Type)
+	} else {
+		errs.Add("", "%v", err)
+	}
+	return errs
+}
+
+// ParseMaterials parses a list of materials written as a Python-style list
+// (['carpet','tiles']), a PostgreSQL array literal ({carpet,tiles}) or a plain
+// comma-separated list.
+func ParseMaterials(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") != strings.HasSuffix(s, "]") ||
+		strings.HasPrefix(s, "{") != strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("unbalanced brackets in %q", s)
+	}
+	s = strings.Trim(s, "[]{}")
+	materials := make([]string, 0)
+	if strings.TrimSpace(s) == "" {
+		return materials, nil
+	}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '\'' || v[0] == '"') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+		if v == "" || strings.ContainsAny(v, `'"[]{}`) {
+			return nil, fmt.Errorf("invalid material %q", v)
+		}
+		materials = append(materials, v)
+	}
+	return materials, nil
+}
@@ -0,0 +1,228 @@
+// Package leads offers stored customer requests to their matched partners and
+// tracks the answer of every partner.
+package leads
+
+import (
+	"aroundHome/app/logging"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Defaults used when the LEADS_* environment variables are not set.
+const (
+	DefaultOffers  = 3
+	DefaultAccepts = 1
+	DefaultTTL     = 48 * time.Hour
+)
+
+// TransitionError is returned when a lead cannot move to the requested state.
+type TransitionError struct {
+	From models.LeadState
+	To   models.LeadState
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("lead is %s and cannot become %s", e.From, e.To)
+}
+
+// Dispatcher offers a customer request to the best ranked partners of its
+// matches. Up to Offers leads are open at a time; whenever a partner declines
+// or lets an offer expire, the next partner in rank is offered the request.
+// Once Accepts partners have accepted, the remaining open leads expire and no
+// more partners are offered the request.
+type Dispatcher struct {
+	Leads    repository.LeadRepository
+	Requests repository.RequestRepository
+	// Offers is the number of leads open at the same time.
+	Offers int
+	// Accepts is the number of partners a request is given to.
+	Accepts int
+	// TTL is the time a partner has to answer an offer.
+	TTL time.Duration
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// FromEnv returns a dispatcher configured by LEADS_OFFERS, LEADS_ACCEPTS and LEADS_TTL.
+func FromEnv(leads repository.LeadRepository, requests repository.RequestRepository) (*Dispatcher, error) {
+	d := &Dispatcher{Leads: leads, Requests: requests, Offers: DefaultOffers, Accepts: DefaultAccepts, TTL: DefaultTTL}
+	for name, n := range map[string]*int{
+		"LEADS_OFFERS":  &d.Offers,
+		"LEADS_ACCEPTS": &d.Accepts,
+	} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 1 {
+			return nil, fmt.Errorf("%s: %q is not a positive integer", name, v)
+		}
+		*n = i
+	}
+	if v := os.Getenv("LEADS_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("LEADS_TTL: %q is not a positive duration", v)
+		}
+		d.TTL = ttl
+	}
+	return d, nil
+}
+
+// Dispatch offers the customer request to its best ranked partners and
+// returns all leads of the request. Dispatching a request again only offers it
+// to further partners if leads were declined or expired in the meantime.
+func (d *Dispatcher) Dispatch(ctx context.Context, requestId int64) ([]*models.Lead, error) {
+	request, err := d.Requests.Get(ctx, requestId)
+	if err != nil {
+		return nil, err
+	}
+	var result []*models.Lead
+	err = d.Leads.Update(ctx, requestId, func(leads []*models.Lead) ([]*models.Lead, error) {
+		result = d.settle(request, leads)
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// View marks a lead as seen by the partner.
+func (d *Dispatcher) View(ctx context.Context, id int64) (*models.Lead, error) {
+	return d.transition(ctx, id, models.LeadViewed)
+}
+
+// Accept accepts a lead for the partner. Reaching the number of accepted
+// partners expires all other open leads of the request.
+func (d *Dispatcher) Accept(ctx context.Context, id int64) (*models.Lead, error) {
+	return d.transition(ctx, id, models.LeadAccepted)
+}
+
+// Decline declines a lead for the partner, the next partner in rank is offered the request.
+func (d *Dispatcher) Decline(ctx context.Context, id int64) (*models.Lead, error) {
+	return d.transition(ctx, id, models.LeadDeclined)
+}
+
+// Expire expires all overdue leads and offers their requests to the next partners.
+func (d *Dispatcher) Expire(ctx context.Context) error {
+	ids, err := d.Leads.Expiring(ctx, d.now())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := d.Dispatch(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run expires overdue leads every interval until ctx is done. Failures are
+// logged and retried with the next tick.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Expire(ctx); err != nil {
+				logging.Error(ctx, "expiring leads failed", "error", err)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) transition(ctx context.Context, id int64, to models.LeadState) (*models.Lead, error) {
+	lead, err := d.Leads.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	request, err := d.Requests.Get(ctx, lead.RequestId)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Leads.Update(ctx, lead.RequestId, func(leads []*models.Lead) ([]*models.Lead, error) {
+		// Settle first, an offer cannot be answered after its deadline or once
+		// enough partners have accepted.
+		leads = d.settle(request, leads)
+		for _, l := range leads {
+			if l.Id != id {
+				continue
+			}
+			if !l.State.CanTransition(to) {
+				return nil, &TransitionError{From: l.State, To: to}
+			}
+			l.State = to
+			lead = l
+		}
+		return d.settle(request, leads), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lead, nil
+}
+
+// settle expires overdue leads, closes the request once enough partners have
+// accepted and otherwise tops up the open leads from the ranked matches.
+func (d *Dispatcher) settle(request *models.CustomerRequest, leads []*models.Lead) []*models.Lead {
+	now := d.now()
+	offered := make(map[int16]bool, len(leads))
+	open, accepted := 0, 0
+	for _, lead := range leads {
+		offered[lead.PartnerId] = true
+		if lead.State.Open() && !now.Before(lead.ExpiresAt) {
+			lead.State = models.LeadExpired
+		}
+		switch {
+		case lead.State.Open():
+			open++
+		case lead.State == models.LeadAccepted:
+			accepted++
+		}
+	}
+
+	if accepted >= d.Accepts {
+		for _, lead := range leads {
+			if lead.State.Open() {
+				lead.State = models.LeadExpired
+			}
+		}
+		return leads
+	}
+
+	for rank, match := range request.Matches {
+		if open >= d.Offers {
+			break
+		}
+		if offered[match.Partner.Id] {
+			continue
+		}
+		leads = append(leads, &models.Lead{
+			RequestId: request.Id,
+			PartnerId: match.Partner.Id,
+			Rank:      rank + 1,
+			State:     models.LeadOffered,
+			OfferedAt: now,
+			ExpiresAt: now.Add(d.TTL),
+		})
+		open++
+	}
+	return leads
+}
+
+func (d *Dispatcher) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,172 @@
+// Package logging writes structured log lines, one JSON object per line with
+// the time, level and message followed by the fields of the line. Lines
+// written for a request carry its id, taken from the context, so all lines of
+// a request can be found by it.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level is the severity of a line. Lines below the level of a logger are dropped.
+type Level int8
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", l)
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses debug, info, warn or error, ignoring case.
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, use debug, info, warn or error", s)
+}
+
+// Logger writes the lines of at least its level to its output. It is safe for
+// concurrent use.
+type Logger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	level Level
+}
+
+// New returns a logger writing the lines of at least level to out.
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{out: out, level: level}
+}
+
+// Enabled reports whether lines of level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Log writes msg with the request id of ctx and fields, given as alternating
+// keys and values like "status", 200. Errors are written by their message and
+// durations like 1.5s. A key without a value gets the value !MISSING.
+func (l *Logger) Log(ctx context.Context, level Level, msg string, fields ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeField(&buf, "time", time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	writeField(&buf, "level", level.String())
+	buf.WriteByte(',')
+	writeField(&buf, "msg", msg)
+	if id := RequestId(ctx); id != "" {
+		buf.WriteByte(',')
+		writeField(&buf, "request_id", id)
+	}
+	for i := 0; i < len(fields); i += 2 {
+		var value interface{} = "!MISSING"
+		if i+1 < len(fields) {
+			value = fields[i+1]
+		}
+		buf.WriteByte(',')
+		writeField(&buf, fmt.Sprint(fields[i]), value)
+	}
+	buf.WriteString("}\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// a line that cannot be written cannot be logged either
+	_, _ = l.out.Write(buf.Bytes())
+}
+
+func writeField(buf *bytes.Buffer, key string, value interface{}) {
+	writeValue(buf, key)
+	buf.WriteByte(':')
+	writeValue(buf, value)
+}
+
+func writeValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Duration:
+		value = v.String()
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(b)
+}
+
+var std atomic.Pointer[Logger]
+
+func init() {
+	std.Store(New(os.Stderr, LevelInfo))
+}
+
+// Default returns the logger of Debug, Info, Warn and Error, which writes the
+// lines of at least LevelInfo to stderr until replaced by SetDefault.
+func Default() *Logger {
+	return std.Load()
+}
+
+// SetDefault replaces the logger of Debug, Info, Warn and Error.
+func SetDefault(l *Logger) {
+	std.Store(l)
+}
+
+// Debug writes msg and fields with the default logger, see Logger.Log.
+func Debug(ctx context.Context, msg string, fields ...interface{}) {
+	Default().Log(ctx, LevelDebug, msg, fields...)
+}
+
+// Info writes msg and fields with the default logger, see Logger.Log.
+func Info(ctx context.Context, msg string, fields ...interface{}) {
+	Default().Log(ctx, LevelInfo, msg, fields...)
+}
+
+// Warn writes msg and fields with the default logger, see Logger.Log.
+func Warn(ctx context.Context, msg string, fields ...interface{}) {
+	Default().Log(ctx, LevelWarn, msg, fields...)
+}
+
+// Error writes msg and fields with the default logger, see Logger.Log.
+func Error(ctx context.Context, msg string, fields ...interface{}) {
+	Default().Log(ctx, LevelError, msg, fields...)
+}
+
+type requestIdKey struct{}
+
+// WithRequestId returns a copy of ctx carrying the request id id.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// RequestId returns the request id of ctx, or "" outside of a request.
+func RequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
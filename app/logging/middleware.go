@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"aroundHome/app/route"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxRequestIdLength bounds the request ids taken from clients.
+const maxRequestIdLength = 128
+
+// resultsKey is the local holding the number of results set by SetResults.
+const resultsKey = "logging.results"
+
+// Middleware gives every request an id and writes its access log line. The id
+// is taken from the X-Request-ID header, or generated when the header is
+// missing or not up to 128 printable ASCII characters. It is answered in the
+// X-Request-ID header and stored in the user context, so the problem details
+// and every line logged for the request carry it.
+//
+// The access log line holds the method, the path without the query string,
+// the route that handled the request, the status, the latency and the number
+// of results set by SetResults. It expects the errors of the handlers to be
+// answered after it, by problem.Middleware, to log their status.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := c.Get(fiber.HeaderXRequestID)
+		if validRequestId(id) {
+			// values of the context point into buffers reused by the next request
+			id = strings.Clone(id)
+		} else {
+			id = newRequestId()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+		ctx := WithRequestId(c.UserContext(), id)
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		fields := []interface{}{
+			"method", c.Method(),
+			"path", c.Path(),
+		}
+		if path := route.Of(c); path != "" {
+			fields = append(fields, "route", path)
+		}
+		fields = append(fields,
+			"status", c.Response().StatusCode(),
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+		)
+		if n, ok := c.Locals(resultsKey).(int); ok {
+			fields = append(fields, "results", n)
+		}
+		Info(ctx, "request", fields...)
+		return err
+	}
+}
+
+// SetResults records the number of results, like matched partners, a handler
+// answers with for the access log line of the request.
+func SetResults(c *fiber.Ctx, n int) {
+	c.Locals(resultsKey, n)
+}
+
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestId returns 16 random bytes in hex, like the trace ids of W3C Trace Context.
+func newRequestId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// the id only correlates log lines, the clock is unique enough then
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
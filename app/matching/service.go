@@ -0,0 +1,112 @@
+// Package matching finds and ranks the partners for a customer request. It is
+// the single implementation behind every endpoint and command that matches.
+package matching
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/ranking"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"aroundHome/app/validation"
+	"context"
+	"sync"
+)
+
+// Request is a customer request to be matched.
+type Request struct {
+	Lat       float64
+	Lng       float64
+	Materials []string
+	// Sqm is the size of the floor in square meters, 0 if unknown.
+	Sqm float64
+}
+
+// Service validates requests against the material catalog, matches them
+// against partners and ranks the result.
+type Service struct {
+	Partners  repository.PartnerRepository
+	Materials *taxonomy.Store
+	Ranker    ranking.Ranker
+}
+
+// Match returns the ranked partners for the request, or validation.Errors if the request is invalid.
+func (s *Service) Match(ctx context.Context, request Request) ([]*models.PartnerWithDistance, error) {
+	if err := s.Validate(ctx, request); err != nil {
+		return nil, err
+	}
+	recs, err := s.Partners.Match(ctx, repository.MatchQuery{Lat: request.Lat, Lng: request.Lng, Materials: request.Materials})
+	if err != nil {
+		return nil, err
+	}
+	s.Ranker.Rank(ranking.Request{Materials: request.Materials, Sqm: request.Sqm}, recs)
+	return recs, nil
+}
+
+// Validate returns validation.Errors if the request cannot be matched.
+func (s *Service) Validate(ctx context.Context, request Request) error {
+	catalog, err := s.Materials.Catalog(ctx)
+	if err != nil {
+		return err
+	}
+	var errs validation.Errors
+	if request.Lat < -90 || request.Lat > 90 {
+		errs.Add("lat", "must be between -90 and 90")
+	}
+	if request.Lng < -180 || request.Lng > 180 {
+		errs.Add("lng", "must be between -180 and 180")
+	}
+	if request.Sqm < 0 {
+		errs.Add("sqm", "must not be negative")
+	}
+	if len(request.Materials) == 0 {
+		errs.Add("materials", "must contain at least one material")
+	}
+	// only materials of the catalog can be matched
+	for _, v := range request.Materials {
+		if err := catalog.Check(v); err != nil {
+			errs.Add("materials", "%v", err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// DefaultConcurrency is the number of requests of a batch matched at the same time.
+const DefaultConcurrency = 8
+
+// BatchResult is the result of one request of a batch, either its ranked
+// partners or the error matching it failed with.
+type BatchResult struct {
+	Matches []*models.PartnerWithDistance
+	Err     error
+}
+
+// MatchBatch matches every request like Match, at most concurrency at the same
+// time. The results are in the order of the requests; a failing request does
+// not stop the others.
+func (s *Service) MatchBatch(ctx context.Context, requests []Request, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	results := make([]BatchResult, len(requests))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(requests); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				matches, err := s.Match(ctx, requests[i])
+				results[i] = BatchResult{Matches: matches, Err: err}
+			}
+		}()
+	}
+	for i := range requests {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
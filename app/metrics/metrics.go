@@ -0,0 +1,96 @@
+// Package metrics exposes the requests, the database pool and the matching of
+// the server to Prometheus. The metrics are registered with the default
+// registry, next to the Go runtime and process metrics.
+package metrics
+
+import (
+	"aroundHome/app/route"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// unmatched is the route label of requests to unknown paths. The path is not
+// used, so scanners cannot create a series per path.
+const unmatched = "unmatched"
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	latency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	matchPartners = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "aroundhome_match_partners",
+		Help:    "Partners returned per query.",
+		Buckets: []float64{0, 1, 2, 3, 5, 10, 20, 50, 100},
+	})
+	matchEmpty = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "aroundhome_match_empty_total",
+		Help: "Queries no partner was found for.",
+	})
+	matchMaterials = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "aroundhome_match_materials_total",
+		Help: "Materials requested by queries.",
+	}, []string{"material"})
+)
+
+// Middleware counts the requests and observes their latency by the route that
+// handled them, like /v1/partners/:id, see route.Of. It expects the errors of
+// the handlers to be answered after it, by problem.Middleware, to count their
+// status.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		path := route.Of(c)
+		if path == "" {
+			path = unmatched
+		}
+		// values of the context point into buffers reused by the next request
+		method := strings.Clone(c.Method())
+		requests.WithLabelValues(method, path, strconv.Itoa(c.Response().StatusCode())).Inc()
+		latency.WithLabelValues(method, path).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// Handler answers the metrics in the Prometheus text format.
+func Handler() fiber.Handler {
+	handler := fasthttpadaptor.NewFastHTTPHandler(promhttp.Handler())
+	return func(c *fiber.Ctx) error {
+		handler(c.Context())
+		return nil
+	}
+}
+
+// RegisterDB exposes the connection pool statistics of db, labeled with name.
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveMatch records a query for materials that found partners partners.
+// The materials are copied, they may be taken from a request.
+func ObserveMatch(materials []string, partners int) {
+	matchPartners.Observe(float64(partners))
+	if partners == 0 {
+		matchEmpty.Inc()
+	}
+	for _, m := range materials {
+		matchMaterials.WithLabelValues(strings.Clone(m)).Inc()
+	}
+}
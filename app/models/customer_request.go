@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CustomerRequest is a stored customer request together with the ranked partners shown for it.
+type CustomerRequest struct {
+	Id        int64
+	Materials []string
+	Lat       float64
+	Lng       float64
+	Sqm       float32
+	Phone     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Matches are the partners as they were shown, later changes to partners do not alter them.
+	Matches []*PartnerWithDistance
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// LeadState is the state of a customer request offered to one partner.
+type LeadState string
+
+const (
+	LeadOffered  LeadState = "offered"
+	LeadViewed   LeadState = "viewed"
+	LeadAccepted LeadState = "accepted"
+	LeadDeclined LeadState = "declined"
+	LeadExpired  LeadState = "expired"
+)
+
+// leadTransitions lists the states a lead may move to from each state.
+// Accepted, declined and expired leads are final.
+var leadTransitions = map[LeadState][]LeadState{
+	LeadOffered: {LeadViewed, LeadAccepted, LeadDeclined, LeadExpired},
+	LeadViewed:  {LeadAccepted, LeadDeclined, LeadExpired},
+}
+
+// CanTransition reports whether a lead in state s may move to state to.
+func (s LeadState) CanTransition(to LeadState) bool {
+	for _, t := range leadTransitions[s] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Open reports whether the partner can still answer a lead in this state.
+func (s LeadState) Open() bool {
+	return s == LeadOffered || s == LeadViewed
+}
+
+// Lead is a customer request offered to one of its matched partners.
+type Lead struct {
+	Id        int64
+	RequestId int64
+	PartnerId int16
+	// Rank is the position of the partner in the matches of the request, starting at 1.
+	Rank      int
+	State     LeadState
+	OfferedAt time.Time
+	ExpiresAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+// Material is a flooring material partners can be experienced with and customers can request.
+// Materials may be grouped under a parent category such as hard_floors; categories themselves
+// cannot be requested.
+type Material struct {
+	Code   string
+	Parent string
+	// Names maps a locale like "en" or "de" to the display name.
+	Names  map[string]string
+	Active bool
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 type Partner struct {
 	Id                 int16
 	Name               string
@@ -7,5 +9,41 @@ type Partner struct {
 	Lng                float32
 	Radius             float32
 	Rating             float32 `minimum:"0" maximum:"10" default:"0"`
-	FlooringExperience string  `enums:"carpet,tiles,wood"`
+	FlooringExperience string  `example:"{carpet,tiles}"`
+	// MinSqm and MaxSqm are the project sizes in square meters the partner prefers, 0 for no limit.
+	MinSqm float32 `minimum:"0" default:"0"`
+	MaxSqm float32 `minimum:"0" default:"0"`
+}
+
+// Materials returns FlooringExperience, which holds a PostgreSQL array literal
+// like {carpet,tiles}, as a list of material names.
+func (p Partner) Materials() []string {
+	literal := strings.Trim(p.FlooringExperience, "{}")
+	if literal == "" {
+		return []string{}
+	}
+	materials := strings.Split(literal, ",")
+	for i, v := range materials {
+		materials[i] = strings.Trim(strings.TrimSpace(v), `"`)
+	}
+	return materials
+}
+
+// HasMaterials reports whether the partner is experienced with every given material.
+func (p Partner) HasMaterials(materials []string) bool {
+	experience := make(map[string]bool)
+	for _, v := range p.Materials() {
+		experience[v] = true
+	}
+	for _, v := range materials {
+		if !experience[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// MaterialsLiteral formats materials as a PostgreSQL array literal suitable for FlooringExperience.
+func MaterialsLiteral(materials []string) string {
+	return "{" + strings.Join(materials, ",") + "}"
 }
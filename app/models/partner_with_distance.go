@@ -3,4 +3,8 @@ package models
 type PartnerWithDistance struct {
 	Partner  Partner
 	Distance float32
+	// Score orders matches, higher is better. Factors holds the contribution
+	// of every ranking factor, adding up to Score.
+	Score   float32
+	Factors map[string]float32
 }
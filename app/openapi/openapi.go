@@ -0,0 +1,57 @@
+// Package openapi serves the OpenAPI 3 description of the API and validates
+// requests against it. The description is converted from the Swagger 2
+// document swag generates from the handler annotations into the docs package,
+// so it is compiled into the binary and cannot drift from the handlers.
+package openapi
+
+import (
+	"aroundHome/docs"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Load returns the OpenAPI 3 description of the API. The unversioned API
+// paths are marked deprecated, like app.Routes marks their responses.
+func Load() (*openapi3.T, error) {
+	doc2 := new(openapi2.T)
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), doc2); err != nil {
+		return nil, err
+	}
+	doc, err := openapi2conv.ToV3(doc2)
+	if err != nil {
+		return nil, err
+	}
+	// the API is served from wherever the binary runs
+	doc.Servers = openapi3.Servers{{URL: "/"}}
+	for path, item := range doc.Paths {
+		for _, operation := range item.Operations() {
+			operation.Deprecated = Deprecated(path)
+			// swag documents lists in the query as csv, which the conversion drops
+			for _, param := range operation.Parameters {
+				if p := param.Value; p.In == openapi3.ParameterInQuery && p.Schema.Value.Type == "array" {
+					p.Style, p.Explode = openapi3.SerializationForm, openapi3.BoolPtr(false)
+				}
+			}
+		}
+	}
+	// the examples of csv parameters are strings, not arrays
+	if err := doc.Validate(context.Background(), openapi3.DisableExamplesValidation()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// unversioned are the paths outside of /v1 that are not deprecated.
+var unversioned = map[string]bool{
+	"/": true, "/healthz": true, "/readyz": true, "/version": true, "/metrics": true, "/graphql": true, "/openapi.json": true,
+}
+
+// Deprecated reports whether path is an unversioned alias of a path under /v1.
+func Deprecated(path string) bool {
+	return !strings.HasPrefix(path, "/v1/") && !unversioned[path] && !strings.HasPrefix(path, "/swagger/")
+}
@@ -0,0 +1,233 @@
+package openapi
+
+import (
+	"aroundHome/app/problem"
+	"aroundHome/app/route"
+	"aroundHome/app/validation"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// rangeFields are the schema fields the handlers check themselves. The domain
+// validation reports every invalid field at once with its own message, so the
+// validator leaves values of the right type to it.
+var rangeFields = map[string]bool{
+	"minimum":          true,
+	"maximum":          true,
+	"exclusiveMinimum": true,
+	"exclusiveMaximum": true,
+	"minLength":        true,
+	"maxLength":        true,
+	"pattern":          true,
+	"minItems":         true,
+	"maxItems":         true,
+}
+
+// Validator returns a middleware rejecting requests to documented operations
+// whose parameters or body do not have the documented shape: missing required
+// parameters, values of the wrong type or not in their enum, and bodies that
+// are no JSON or not of the documented content type. Invalid parameters are
+// answered 400, invalid bodies 422 with the field errors. Requests to paths not
+// in doc are left to the routes. Rejected requests are recorded with the route
+// of their operation, see route.Set.
+func Validator(doc *openapi3.T) (fiber.Handler, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{MultiError: true, SkipSettingDefaults: true}
+
+	return func(c *fiber.Ctx) error {
+		req := new(http.Request)
+		if err := fasthttpadaptor.ConvertRequest(c.Context(), req, true); err != nil {
+			return err
+		}
+		// routes match with and without a trailing slash
+		if path := req.URL.Path; len(path) > 1 {
+			req.URL.Path = strings.TrimSuffix(path, "/")
+		}
+		operation, params, err := router.FindRoute(req)
+		if err != nil {
+			return c.Next()
+		}
+		err = openapi3filter.ValidateRequest(c.UserContext(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: params,
+			Route:      operation,
+			Options:    options,
+		})
+		if err != nil {
+			if err := requestError(err); err != nil {
+				route.Set(c, fiberPath(operation.Path))
+				return err
+			}
+		}
+		return c.Next()
+	}, nil
+}
+
+// fiberPath writes the parameters of an OpenAPI path like those of the routes,
+// /partners/{id} as /partners/:id.
+func fiberPath(path string) string {
+	return pathParam.ReplaceAllString(path, ":$1")
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// requestError converts the errors of ValidateRequest to the error answered,
+// nil if only value ranges are invalid.
+func requestError(err error) error {
+	var params, fields validation.Errors
+	for _, err := range unpack(err) {
+		var reqErr *openapi3filter.RequestError
+		if !errors.As(err, &reqErr) {
+			return err
+		}
+		switch {
+		case reqErr.Parameter != nil:
+			for _, e := range fieldErrors(reqErr.Err) {
+				if e.skip {
+					continue
+				}
+				params.Add(reqErr.Parameter.Name, "%s", e.message(reqErr.Parameter.Schema))
+			}
+		case reqErr.RequestBody != nil:
+			var schemaErr *openapi3.SchemaError
+			if !errors.As(reqErr.Err, &schemaErr) {
+				// no JSON, no body or an unknown content type
+				return problem.BadRequest(reqErr.Error())
+			}
+			for _, e := range fieldErrors(reqErr.Err) {
+				if e.skip {
+					continue
+				}
+				fields.Add(e.field, "%s", e.message(nil))
+			}
+		default:
+			return problem.BadRequest(reqErr.Error())
+		}
+	}
+	if len(params) > 0 {
+		return problem.InvalidParameters(params)
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	return nil
+}
+
+// unpack flattens the errors collected with MultiError. The errors of a
+// request are not unwrapped, MultiError.As would find the errors they wrap.
+func unpack(err error) []error {
+	multi, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []error{err}
+	}
+	var errs []error
+	for _, err := range multi {
+		errs = append(errs, unpack(err)...)
+	}
+	return errs
+}
+
+// fieldError is a single invalid value of a parameter or body.
+type fieldError struct {
+	field  string
+	schema *openapi3.SchemaError
+	err    error
+	skip   bool
+}
+
+func fieldErrors(err error) []fieldError {
+	var errs []fieldError
+	for _, err := range unpack(err) {
+		var schemaErr *openapi3.SchemaError
+		if !errors.As(err, &schemaErr) {
+			errs = append(errs, fieldError{err: err})
+			continue
+		}
+		errs = append(errs, fieldError{
+			field:  fieldName(schemaErr.JSONPointer()),
+			schema: schemaErr,
+			skip:   rangeFields[schemaErr.SchemaField],
+		})
+	}
+	return errs
+}
+
+// message describes the error like the handlers do. Parameters that cannot be
+// parsed are described by their schema.
+func (e fieldError) message(param *openapi3.SchemaRef) string {
+	if e.schema == nil {
+		switch {
+		case errors.Is(e.err, openapi3filter.ErrInvalidRequired), errors.Is(e.err, openapi3filter.ErrInvalidEmptyValue):
+			return "is required"
+		case param != nil && param.Value != nil:
+			return "must be " + describe(param.Value)
+		}
+		return e.err.Error()
+	}
+	switch e.schema.SchemaField {
+	case "type":
+		return "must be " + describe(e.schema.Schema)
+	case "enum":
+		values := make([]string, len(e.schema.Schema.Enum))
+		for i, v := range e.schema.Schema.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		return "must be one of " + strings.Join(values, ", ")
+	case "required":
+		return "is required"
+	}
+	return e.schema.Reason
+}
+
+// describe names the type of schema, like "a number" or "a list of integers".
+func describe(schema *openapi3.Schema) string {
+	switch schema.Type {
+	case "array":
+		if schema.Items != nil && schema.Items.Value != nil {
+			return "a list of " + schema.Items.Value.Type + "s"
+		}
+		return "a list"
+	case "integer":
+		return "an integer"
+	case "object":
+		return "an object"
+	}
+	return "a " + schema.Type
+}
+
+// fieldName names a value of the body by its path, in the snake case the
+// validation of the handlers uses, e.g. FlooringExperience is flooring_experience.
+func fieldName(pointer []string) string {
+	parts := make([]string, len(pointer))
+	for i, p := range pointer {
+		parts[i] = snakeCase(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(rune(s[i-1])) && s[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package problem
+
+import (
+	"github.com/gofiber/fiber/v2"
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+// Code is the code the APIs without HTTP statuses, GraphQL and gRPC, answer
+// an error with.
+type Code struct {
+	GraphQL string
+	GRPC    grpccodes.Code
+}
+
+// Internal is the Code of errors not caused by the request.
+var Internal = Code{GraphQL: "INTERNAL", GRPC: grpccodes.Internal}
+
+// codesByStatus maps the statuses From assigns to errors caused by the
+// request to their Code, so both APIs classify errors alike.
+var codesByStatus = map[int]Code{
+	fiber.StatusBadRequest:          {GraphQL: "BAD_USER_INPUT", GRPC: grpccodes.InvalidArgument},
+	fiber.StatusNotFound:            {GraphQL: "NOT_FOUND", GRPC: grpccodes.NotFound},
+	fiber.StatusConflict:            {GraphQL: "CONFLICT", GRPC: grpccodes.FailedPrecondition},
+	fiber.StatusUnprocessableEntity: {GraphQL: "BAD_USER_INPUT", GRPC: grpccodes.InvalidArgument},
+}
+
+// Code returns the Code of the problem, and false with Internal if it was not
+// caused by the request.
+func (p *Problem) Code() (Code, bool) {
+	code, ok := codesByStatus[p.Status]
+	if !ok {
+		return Internal, false
+	}
+	return code, true
+}
@@ -0,0 +1,129 @@
+// Package problem answers errors returned by handlers as problem details
+// (RFC 7807) with the status matching the error.
+package problem
+
+import (
+	"aroundHome/app/leads"
+	"aroundHome/app/logging"
+	"aroundHome/app/repository"
+	"aroundHome/app/validation"
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Problem is the body of an error response.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// RequestId is the X-Request-ID of the request, to find its log lines.
+	RequestId string `json:"request_id,omitempty"`
+	// Errors are the field errors of invalid input.
+	Errors validation.Errors `json:"errors,omitempty"`
+}
+
+// Error is an error answered with the given status and detail. Handlers use it
+// for errors that have no meaning outside of HTTP, like unparsable parameters.
+type Error struct {
+	Status int
+	Detail string
+	Errors validation.Errors
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// BadRequest is an error for a request that cannot be read.
+func BadRequest(detail string) *Error {
+	return &Error{Status: fiber.StatusBadRequest, Detail: detail}
+}
+
+// InvalidParameters is an error for invalid path or query parameters. Invalid
+// request bodies are answered 422 by returning the validation.Errors as they are.
+func InvalidParameters(errs validation.Errors) *Error {
+	return &Error{Status: fiber.StatusBadRequest, Detail: errs.Error(), Errors: errs}
+}
+
+// notFound and conflict are the errors of the data access and lead workflow
+// that are caused by the request rather than by the server.
+var (
+	notFound = []error{repository.ErrNotFound, repository.ErrRequestNotFound, repository.ErrLeadNotFound, repository.ErrMaterialNotFound}
+	conflict = []error{repository.ErrExists, repository.ErrMaterialInUse}
+)
+
+// From returns the problem details for err. Errors not caused by the request
+// are a 500 without details, so no internals leak to clients.
+func From(err error) *Problem {
+	var (
+		appErr        *Error
+		fiberErr      *fiber.Error
+		errs          validation.Errors
+		transitionErr *leads.TransitionError
+	)
+	switch {
+	case errors.As(err, &appErr):
+		return newProblem(appErr.Status, appErr.Detail, appErr.Errors)
+	case errors.As(err, &fiberErr):
+		return newProblem(fiberErr.Code, fiberErr.Message, nil)
+	case errors.As(err, &errs):
+		return newProblem(fiber.StatusUnprocessableEntity, errs.Error(), errs)
+	case errors.As(err, &transitionErr), isAny(err, conflict):
+		return newProblem(fiber.StatusConflict, err.Error(), nil)
+	case isAny(err, notFound):
+		return newProblem(fiber.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, repository.ErrInvalidCursor):
+		return newProblem(fiber.StatusBadRequest, err.Error(), nil)
+	default:
+		return newProblem(fiber.StatusInternalServerError, "", nil)
+	}
+}
+
+// Handler is the fiber.ErrorHandler of the application.
+func Handler(c *fiber.Ctx, err error) error {
+	p := From(err)
+	p.Instance = c.OriginalURL()
+	p.RequestId = logging.RequestId(c.UserContext())
+	if p.Status >= fiber.StatusInternalServerError {
+		logging.Error(c.UserContext(), "request failed", "method", c.Method(), "path", c.Path(), "error", err)
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, ContentType)
+	return c.Status(p.Status).Send(body)
+}
+
+// Middleware answers the errors of the handlers after it with Handler right
+// away instead of leaving them to the app, so the middleware before it, like
+// the access log, the tracing and the metrics, sees the final status of every
+// request.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return Handler(c, err)
+		}
+		return nil
+	}
+}
+
+func newProblem(status int, detail string, errs validation.Errors) *Problem {
+	return &Problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Detail: detail, Errors: errs}
+}
+
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Package ranking orders matched partners by how well they fit a customer request.
+package ranking
+
+import (
+	"aroundHome/app/models"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Request holds what is known about the customer request beyond the location.
+type Request struct {
+	Materials []string
+	// Sqm is the size of the floor in square meters, 0 if unknown.
+	Sqm float64
+}
+
+// Ranker orders matches best first and sets their Score and Factors.
+type Ranker interface {
+	Rank(request Request, matches []*models.PartnerWithDistance)
+}
+
+// Default ranks by rating and then by distance, the order of the original query.
+// The score is the rating.
+type Default struct{}
+
+func (Default) Rank(_ Request, matches []*models.PartnerWithDistance) {
+	for _, m := range matches {
+		m.Score = m.Partner.Rating
+		m.Factors = map[string]float32{"rating": m.Partner.Rating}
+	}
+	sortByScore(matches)
+}
+
+// Weights of the factors combined by Weighted. Negative weights turn a factor into a penalty.
+type Weights struct {
+	Rating    float64
+	Distance  float64
+	Materials float64
+	Size      float64
+}
+
+// DefaultWeights still favor the rating, but let a close partner beat a slightly better rated one far away.
+var DefaultWeights = Weights{Rating: 0.6, Distance: 0.3, Materials: 0.05, Size: 0.05}
+
+// Weighted scores every match as the weighted sum of factors between 0 and 1:
+//   - rating: the rating divided by the maximum of 10
+//   - distance: 1 at the partner's office, 0 at the edge of the operating radius
+//   - materials: the share of the partner's materials beyond the requested ones
+//   - size: 1 if the floor fits the partner's preferred project size or either is unknown,
+//     decreasing with the ratio the floor is too small or too large
+type Weighted struct {
+	Weights Weights
+}
+
+func (w Weighted) Rank(request Request, matches []*models.PartnerWithDistance) {
+	for _, m := range matches {
+		rating := float32(w.Weights.Rating * float64(m.Partner.Rating) / 10)
+		distance := float32(w.Weights.Distance * proximity(m))
+		materials := float32(w.Weights.Materials * surplus(m.Partner, request.Materials))
+		size := float32(w.Weights.Size * sizeFit(m.Partner, request.Sqm))
+		m.Score = rating + distance + materials + size
+		m.Factors = map[string]float32{"rating": rating, "distance": distance, "materials": materials, "size": size}
+	}
+	sortByScore(matches)
+}
+
+func proximity(m *models.PartnerWithDistance) float64 {
+	if m.Partner.Radius <= 0 {
+		return 0
+	}
+	p := 1 - float64(m.Distance)/float64(m.Partner.Radius)
+	if p < 0 {
+		return 0
+	}
+	return p
+}
+
+func surplus(p models.Partner, requested []string) float64 {
+	offered := len(p.Materials())
+	if offered == 0 || offered <= len(requested) {
+		return 0
+	}
+	return float64(offered-len(requested)) / float64(offered)
+}
+
+func sizeFit(p models.Partner, sqm float64) float64 {
+	switch {
+	case sqm <= 0:
+		return 1
+	case p.MinSqm > 0 && sqm < float64(p.MinSqm):
+		return sqm / float64(p.MinSqm)
+	case p.MaxSqm > 0 && sqm > float64(p.MaxSqm):
+		return float64(p.MaxSqm) / sqm
+	}
+	return 1
+}
+
+// sortByScore orders matches by score, best first, and then by distance.
+func sortByScore(matches []*models.PartnerWithDistance) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Distance < matches[j].Distance
+	})
+}
+
+// FromEnv returns the Ranker selected by the RANKING environment variable,
+// default or weighted. Weights are read from RANKING_WEIGHT_RATING,
+// RANKING_WEIGHT_DISTANCE, RANKING_WEIGHT_MATERIALS and RANKING_WEIGHT_SIZE,
+// falling back to DefaultWeights.
+func FromEnv() (Ranker, error) {
+	switch strategy := os.Getenv("RANKING"); strategy {
+	case "", "default":
+		return Default{}, nil
+	case "weighted":
+		weights := DefaultWeights
+		for name, weight := range map[string]*float64{
+			"RANKING_WEIGHT_RATING":    &weights.Rating,
+			"RANKING_WEIGHT_DISTANCE":  &weights.Distance,
+			"RANKING_WEIGHT_MATERIALS": &weights.Materials,
+			"RANKING_WEIGHT_SIZE":      &weights.Size,
+		} {
+			v := os.Getenv(name)
+			if v == "" {
+				continue
+			}
+			w, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q is not a number", name, v)
+			}
+			*weight = w
+		}
+		return Weighted{Weights: weights}, nil
+	default:
+		return nil, fmt.Errorf("unknown RANKING %q, use default or weighted", strategy)
+	}
+}
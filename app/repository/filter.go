@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"aroundHome/app/geo"
+	"aroundHome/app/models"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCursor is returned by ParseCursor for cursors not issued for the requested sort.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sort orders the partners returned by Find. Every order ends with the id, so
+// it is total and pages neither repeat nor skip partners.
+type Sort string
+
+const (
+	SortId         Sort = "id"
+	SortName       Sort = "name"
+	SortRating     Sort = "rating"
+	SortRatingDesc Sort = "-rating"
+)
+
+// Sorts lists all supported orders.
+var Sorts = []Sort{SortId, SortName, SortRating, SortRatingDesc}
+
+// ParseSort returns the Sort named s.
+func ParseSort(s string) (Sort, error) {
+	for _, sort := range Sorts {
+		if string(sort) == s {
+			return sort, nil
+		}
+	}
+	names := make([]string, len(Sorts))
+	for i, sort := range Sorts {
+		names[i] = string(sort)
+	}
+	return "", fmt.Errorf("unknown sort %q, use one of %s", s, strings.Join(names, ", "))
+}
+
+// Cursor is the position after the last partner of a page.
+type Cursor struct {
+	Sort   Sort    `json:"s"`
+	Id     int16   `json:"i"`
+	Name   string  `json:"n,omitempty"`
+	Rating float32 `json:"r,omitempty"`
+}
+
+// cursorAfter returns the cursor positioned after p in the given order.
+func cursorAfter(p *models.Partner, sort Sort) *Cursor {
+	c := &Cursor{Sort: sort, Id: p.Id}
+	switch sort {
+	case SortName:
+		c.Name = p.Name
+	case SortRating, SortRatingDesc:
+		c.Rating = p.Rating
+	}
+	return c
+}
+
+// String encodes the cursor as an opaque token.
+func (c *Cursor) String() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseCursor decodes a token returned by Cursor.String for the given order.
+func ParseCursor(token string, sort Sort) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := new(Cursor)
+	if err := json.Unmarshal(b, c); err != nil || c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// PartnerFilter selects a page of partners for Find. Empty fields do not filter.
+type PartnerFilter struct {
+	Ids []int16
+	// Materials the partners must all be experienced with.
+	Materials []string
+	// AnyMaterials of which the partners must be experienced with at least one.
+	AnyMaterials []string
+	MinRating    *float32
+	MaxRating    *float32
+	// NamePrefix matches the start of the name ignoring case.
+	NamePrefix string
+	// Box contains the office of the partners.
+	Box   *geo.Box
+	Sort  Sort
+	After *Cursor
+	// Limit is the maximum number of partners returned.
+	Limit int
+}
+
+// PartnerPage is a page of partners returned by Find.
+type PartnerPage struct {
+	Partners []*models.Partner
+	// Next is the cursor of the following page, nil on the last page.
+	Next *Cursor
+}
+
+// Accepts reports whether p passes all filters apart from the cursor.
+func (f *PartnerFilter) Accepts(p *models.Partner) bool {
+	if len(f.Ids) > 0 && !containsId(f.Ids, p.Id) {
+		return false
+	}
+	if !p.HasMaterials(f.Materials) {
+		return false
+	}
+	if len(f.AnyMaterials) > 0 && !hasAnyMaterial(p, f.AnyMaterials) {
+		return false
+	}
+	if f.MinRating != nil && p.Rating < *f.MinRating {
+		return false
+	}
+	if f.MaxRating != nil && p.Rating > *f.MaxRating {
+		return false
+	}
+	if f.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(p.Name), strings.ToLower(f.NamePrefix)) {
+		return false
+	}
+	if f.Box != nil && !f.Box.Contains(float64(p.Lat), float64(p.Lng)) {
+		return false
+	}
+	return true
+}
+
+// Less reports whether a comes before b in the order of the filter.
+func (f *PartnerFilter) Less(a, b *models.Partner) bool {
+	switch f.Sort {
+	case SortName:
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+	case SortRating:
+		if a.Rating != b.Rating {
+			return a.Rating < b.Rating
+		}
+	case SortRatingDesc:
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		return a.Id > b.Id
+	}
+	return a.Id < b.Id
+}
+
+// afterCursor reports whether p comes after the cursor of the filter.
+func (f *PartnerFilter) afterCursor(p *models.Partner) bool {
+	if f.After == nil {
+		return true
+	}
+	last := &models.Partner{Id: f.After.Id, Name: f.After.Name, Rating: f.After.Rating}
+	return f.Less(last, p)
+}
+
+func containsId(ids []int16, id int16) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyMaterial(p *models.Partner, materials []string) bool {
+	for _, v := range materials {
+		if p.HasMaterials([]string{v}) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+)
+
+func (r *Postgres) Find(ctx context.Context, filter PartnerFilter) (page *PartnerPage, err error) {
+	query, args := findSql(filter)
+	ctx, span := startStatement(ctx, "partners.find", query)
+	defer func() {
+		rows := 0
+		if page != nil {
+			rows = len(page.Partners)
+		}
+		span.end(rows, err)
+	}()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page = &PartnerPage{Partners: make([]*models.Partner, 0, filter.Limit)}
+	for rows.Next() {
+		rec := new(models.Partner)
+		if err := scanPartner(rows, rec); err != nil {
+			return nil, err
+		}
+		page.Partners = append(page.Partners, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// one partner more than the limit is selected to know whether a next page exists
+	if len(page.Partners) > filter.Limit {
+		page.Partners = page.Partners[:filter.Limit]
+		page.Next = cursorAfter(page.Partners[filter.Limit-1], filter.Sort)
+	}
+	return page, nil
+}
+
+// sortColumns are the columns the sorts order by, compared as a row with the
+// values of the cursor. Names are compared bytewise and ratings as real like
+// models.Partner holds them, so cursors compare equal to the rows they came from.
+var sortColumns = map[Sort]string{
+	SortId:         "id",
+	SortName:       `name collate "C", id`,
+	SortRating:     "coalesce(rating, 0)::real, id",
+	SortRatingDesc: "coalesce(rating, 0)::real, id",
+}
+
+var sortOrders = map[Sort]string{
+	SortId:         "id",
+	SortName:       `name collate "C", id`,
+	SortRating:     "coalesce(rating, 0)::real, id",
+	SortRatingDesc: "coalesce(rating, 0)::real desc, id desc",
+}
+
+// findSql builds the query for filter, selecting up to filter.Limit + 1 partners.
+func findSql(filter PartnerFilter) (string, []interface{}) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(filter.Ids) > 0 {
+		ids := make([]int64, len(filter.Ids))
+		for i, id := range filter.Ids {
+			ids[i] = int64(id)
+		}
+		where = append(where, "id = any("+arg(pq.Int64Array(ids))+")")
+	}
+	if len(filter.Materials) > 0 {
+		where = append(where, "flooring_experience @> "+arg(pq.Array(filter.Materials)))
+	}
+	if len(filter.AnyMaterials) > 0 {
+		where = append(where, "flooring_experience && "+arg(pq.Array(filter.AnyMaterials)))
+	}
+	if filter.MinRating != nil {
+		where = append(where, "coalesce(rating, 0)::real >= "+arg(*filter.MinRating))
+	}
+	if filter.MaxRating != nil {
+		where = append(where, "coalesce(rating, 0)::real <= "+arg(*filter.MaxRating))
+	}
+	if filter.NamePrefix != "" {
+		prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(filter.NamePrefix))
+		where = append(where, "lower(name) like "+arg(prefix+"%"))
+	}
+	if box := filter.Box; box != nil {
+		where = append(where, "lat between "+arg(box.MinLat)+" and "+arg(box.MaxLat))
+		if box.CrossesAntimeridian() {
+			where = append(where, "(lng >= "+arg(box.MinLng)+" or lng <= "+arg(box.MaxLng)+")")
+		} else {
+			where = append(where, "lng between "+arg(box.MinLng)+" and "+arg(box.MaxLng))
+		}
+	}
+
+	columns := sortColumns[filter.Sort]
+	if c := filter.After; c != nil {
+		var values string
+		switch filter.Sort {
+		case SortName:
+			values = arg(c.Name) + ", " + arg(c.Id)
+		case SortRating, SortRatingDesc:
+			values = arg(c.Rating) + "::real, " + arg(c.Id)
+		default:
+			values = arg(c.Id)
+		}
+		operator := ">"
+		if filter.Sort == SortRatingDesc {
+			operator = "<"
+		}
+		where = append(where, "("+columns+") "+operator+" ("+values+")")
+	}
+
+	query := "select\n    Id, Name, Lat, Lng, Radius, Rating, flooring_experience AS FlooringExperience,\n    coalesce(min_sqm, 0) AS MinSqm, coalesce(max_sqm, 0) AS MaxSqm\nfrom\n    partners\n"
+	if len(where) > 0 {
+		query += "where\n    " + strings.Join(where, "\n    AND ") + "\n"
+	}
+	query += "order by\n    " + sortOrders[filter.Sort] + "\nlimit " + arg(filter.Limit+1) + ";"
+	return query, args
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLeadNotFound is returned when a lead with the requested id does not exist.
+var ErrLeadNotFound = errors.New("lead not found")
+
+// LeadRepository stores the leads of customer requests.
+type LeadRepository interface {
+	// Get returns the lead with the given id, or ErrLeadNotFound.
+	Get(ctx context.Context, id int64) (*models.Lead, error)
+	// ForRequest returns the leads of a customer request ordered by rank.
+	ForRequest(ctx context.Context, requestId int64) ([]*models.Lead, error)
+	// Update passes the leads of a customer request to fn while no other Update
+	// for the same request can run and stores the leads fn returns. Leads
+	// without an id are inserted, all others updated. Nothing is stored if fn
+	// returns an error.
+	Update(ctx context.Context, requestId int64, fn func(leads []*models.Lead) ([]*models.Lead, error)) error
+	// Expiring returns the ids of customer requests having offered or viewed
+	// leads that expire before the given time.
+	Expiring(ctx context.Context, before time.Time) ([]int64, error)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryLeads is a LeadRepository keeping leads in process memory. Update
+// holds a single lock, so all updates are serialized.
+type MemoryLeads struct {
+	mu     sync.RWMutex
+	lastId int64
+	leads  map[int64]models.Lead
+}
+
+func NewMemoryLeads() *MemoryLeads {
+	return &MemoryLeads{leads: make(map[int64]models.Lead)}
+}
+
+func (r *MemoryLeads) Get(_ context.Context, id int64) (*models.Lead, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lead, ok := r.leads[id]
+	if !ok {
+		return nil, ErrLeadNotFound
+	}
+	return &lead, nil
+}
+
+func (r *MemoryLeads) ForRequest(_ context.Context, requestId int64) ([]*models.Lead, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.forRequest(requestId), nil
+}
+
+func (r *MemoryLeads) Update(_ context.Context, requestId int64, fn func(leads []*models.Lead) ([]*models.Lead, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	leads, err := fn(r.forRequest(requestId))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, lead := range leads {
+		lead.RequestId = requestId
+		if lead.Id == 0 {
+			r.lastId++
+			lead.Id = r.lastId
+			lead.UpdatedAt = now
+		} else if stored := r.leads[lead.Id]; stored.State != lead.State {
+			lead.UpdatedAt = now
+		}
+		r.leads[lead.Id] = *lead
+	}
+	return nil
+}
+
+func (r *MemoryLeads) Expiring(_ context.Context, before time.Time) ([]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seen := make(map[int64]bool)
+	ids := make([]int64, 0)
+	for _, lead := range r.leads {
+		if lead.State.Open() && lead.ExpiresAt.Before(before) && !seen[lead.RequestId] {
+			seen[lead.RequestId] = true
+			ids = append(ids, lead.RequestId)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *MemoryLeads) forRequest(requestId int64) []*models.Lead {
+	leads := make([]*models.Lead, 0)
+	for _, lead := range r.leads {
+		if lead.RequestId == requestId {
+			lead := lead
+			leads = append(leads, &lead)
+		}
+	}
+	sort.Slice(leads, func(i, j int) bool { return leads[i].Rank < leads[j].Rank })
+	return leads
+}
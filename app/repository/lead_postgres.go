@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PostgresLeads is a LeadRepository backed by the leads table. Update locks
+// the row of the customer request, so concurrent updates of its leads are
+// serialized.
+type PostgresLeads struct {
+	db *sql.DB
+}
+
+func NewPostgresLeads(db *sql.DB) *PostgresLeads {
+	return &PostgresLeads{db: db}
+}
+
+func (r *PostgresLeads) Get(ctx context.Context, id int64) (lead *models.Lead, err error) {
+	ctx, span := startStatement(ctx, "leads.get", leadSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	lead, err = scanLead(r.db.QueryRowContext(ctx, leadSql(), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrLeadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lead, nil
+}
+
+func (r *PostgresLeads) ForRequest(ctx context.Context, requestId int64) (leads []*models.Lead, err error) {
+	ctx, span := startStatement(ctx, "leads.for_request", requestLeadsSql())
+	defer func() { span.end(len(leads), err) }()
+
+	return queryLeads(ctx, r.db, requestId)
+}
+
+func (r *PostgresLeads) Update(ctx context.Context, requestId int64, fn func(leads []*models.Lead) ([]*models.Lead, error)) (err error) {
+	ctx, span := startStatement(ctx, "leads.update", updateLeadSql())
+	var (
+		leads   []*models.Lead
+		refused error
+	)
+	defer func() {
+		failure := err
+		if err == refused {
+			// fn refusing the change is no failure of the statements
+			failure = nil
+		}
+		span.end(len(leads), failure)
+	}()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	err = tx.QueryRowContext(ctx, lockRequestSql(), requestId).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return ErrRequestNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	leads, err = queryLeads(ctx, tx, requestId)
+	if err != nil {
+		return err
+	}
+	leads, err = fn(leads)
+	if err != nil {
+		refused = err
+		return err
+	}
+
+	for _, lead := range leads {
+		if lead.Id == 0 {
+			err = tx.QueryRowContext(ctx, insertLeadSql(), requestId, lead.PartnerId, lead.Rank, lead.State, lead.OfferedAt, lead.ExpiresAt).
+				Scan(&lead.Id, &lead.UpdatedAt)
+		} else {
+			err = tx.QueryRowContext(ctx, updateLeadSql(), lead.Id, lead.State).Scan(&lead.UpdatedAt)
+		}
+		if err != nil {
+			return err
+		}
+		lead.RequestId = requestId
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresLeads) Expiring(ctx context.Context, before time.Time) (ids []int64, err error) {
+	ctx, span := startStatement(ctx, "leads.expiring", expiringLeadsSql())
+	defer func() { span.end(len(ids), err) }()
+
+	rows, err := r.db.QueryContext(ctx, expiringLeadsSql(), before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids = make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func queryLeads(ctx context.Context, q querier, requestId int64) ([]*models.Lead, error) {
+	rows, err := q.QueryContext(ctx, requestLeadsSql(), requestId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leads := make([]*models.Lead, 0)
+	for rows.Next() {
+		lead, err := scanLead(rows)
+		if err != nil {
+			return nil, err
+		}
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+func scanLead(row scanner) (*models.Lead, error) {
+	lead := new(models.Lead)
+	err := row.Scan(&lead.Id, &lead.RequestId, &lead.PartnerId, &lead.Rank, &lead.State, &lead.OfferedAt, &lead.ExpiresAt, &lead.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return lead, nil
+}
+
+const leadColumns = "id, request_id, partner_id, rank, state, offered_at, expires_at, updated_at"
+
+func leadSql() string {
+	return "select\n    " + leadColumns + "\nfrom\n    leads\nwhere\n    id = $1;"
+}
+
+func requestLeadsSql() string {
+	return "select\n    " + leadColumns + "\nfrom\n    leads\nwhere\n    request_id = $1\norder by\n    rank;"
+}
+
+func lockRequestSql() string {
+	return "select\n    id\nfrom\n    customer_requests\nwhere\n    id = $1\nfor update;"
+}
+
+func insertLeadSql() string {
+	return "insert into leads\n    (request_id, partner_id, rank, state, offered_at, expires_at)\nvalues\n    ($1, $2, $3, $4, $5, $6)\nreturning\n    id, updated_at;"
+}
+
+func updateLeadSql() string {
+	return "update leads\nset\n    state = $2,\n    updated_at = case when state = $2 then updated_at else now() end\nwhere\n    id = $1\nreturning\n    updated_at;"
+}
+
+func expiringLeadsSql() string {
+	return "select distinct\n    request_id\nfrom\n    leads\nwhere\n    state in ('offered', 'viewed') and expires_at < $1;"
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"errors"
+)
+
+var (
+	// ErrMaterialNotFound is returned when a material with the requested code does not exist.
+	ErrMaterialNotFound = errors.New("material not found")
+	// ErrMaterialInUse is returned when deleting a material other materials belong to.
+	ErrMaterialInUse = errors.New("material is the parent of other materials")
+)
+
+// MaterialRepository provides access to the material taxonomy.
+type MaterialRepository interface {
+	// List returns all materials, active or not, ordered by code.
+	List(ctx context.Context) ([]*models.Material, error)
+	// Get returns the material with the given code or ErrMaterialNotFound.
+	Get(ctx context.Context, code string) (*models.Material, error)
+	// Upsert inserts the material or replaces an existing one with the same code.
+	Upsert(ctx context.Context, material *models.Material) error
+	// Delete removes the material, ErrMaterialNotFound or ErrMaterialInUse are returned if it cannot be.
+	Delete(ctx context.Context, code string) error
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryMaterials is a MaterialRepository keeping materials in process memory.
+type MemoryMaterials struct {
+	mu        sync.RWMutex
+	materials map[string]models.Material
+}
+
+func NewMemoryMaterials(materials ...*models.Material) *MemoryMaterials {
+	r := &MemoryMaterials{materials: make(map[string]models.Material)}
+	for _, m := range materials {
+		r.materials[m.Code] = copyMaterial(m)
+	}
+	return r
+}
+
+func (r *MemoryMaterials) List(_ context.Context) ([]*models.Material, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	recs := make([]*models.Material, 0, len(r.materials))
+	for _, m := range r.materials {
+		m := copyMaterial(&m)
+		recs = append(recs, &m)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Code < recs[j].Code })
+	return recs, nil
+}
+
+func (r *MemoryMaterials) Get(_ context.Context, code string) (*models.Material, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.materials[code]
+	if !ok {
+		return nil, ErrMaterialNotFound
+	}
+	m = copyMaterial(&m)
+	return &m, nil
+}
+
+func (r *MemoryMaterials) Upsert(_ context.Context, material *models.Material) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.materials[material.Code] = copyMaterial(material)
+	return nil
+}
+
+func (r *MemoryMaterials) Delete(_ context.Context, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.materials[code]; !ok {
+		return ErrMaterialNotFound
+	}
+	for _, m := range r.materials {
+		if m.Parent == code {
+			return ErrMaterialInUse
+		}
+	}
+	delete(r.materials, code)
+	return nil
+}
+
+// copyMaterial copies m including its names so callers cannot modify stored materials.
+func copyMaterial(m *models.Material) models.Material {
+	c := *m
+	c.Names = make(map[string]string, len(m.Names))
+	for locale, name := range m.Names {
+		c.Names[locale] = name
+	}
+	return c
+}
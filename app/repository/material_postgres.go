@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/lib/pq"
+)
+
+// PostgresMaterials is a MaterialRepository backed by the materials table.
+type PostgresMaterials struct {
+	db *sql.DB
+}
+
+func NewPostgresMaterials(db *sql.DB) *PostgresMaterials {
+	return &PostgresMaterials{db: db}
+}
+
+func (r *PostgresMaterials) List(ctx context.Context) (recs []*models.Material, err error) {
+	ctx, span := startStatement(ctx, "materials.list", materialsSql())
+	defer func() { span.end(len(recs), err) }()
+
+	rows, err := r.db.QueryContext(ctx, materialsSql())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recs = make([]*models.Material, 0)
+	for rows.Next() {
+		rec, err := scanMaterial(rows)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, rows.Err()
+}
+
+func (r *PostgresMaterials) Get(ctx context.Context, code string) (rec *models.Material, err error) {
+	ctx, span := startStatement(ctx, "materials.get", materialSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	rec, err = scanMaterial(r.db.QueryRowContext(ctx, materialSql(), code))
+	if err == sql.ErrNoRows {
+		return nil, ErrMaterialNotFound
+	}
+	return rec, err
+}
+
+func (r *PostgresMaterials) Upsert(ctx context.Context, material *models.Material) (err error) {
+	ctx, span := startStatement(ctx, "materials.upsert", upsertMaterialSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	names, err := json.Marshal(material.Names)
+	if err != nil {
+		return err
+	}
+	parent := sql.NullString{String: material.Parent, Valid: material.Parent != ""}
+	_, err = r.db.ExecContext(ctx, upsertMaterialSql(), material.Code, parent, names, material.Active)
+	return err
+}
+
+func (r *PostgresMaterials) Delete(ctx context.Context, code string) (err error) {
+	ctx, span := startStatement(ctx, "materials.delete", deleteMaterialSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	result, err := r.db.ExecContext(ctx, deleteMaterialSql(), code)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		// foreign_key_violation: other materials still reference it as parent
+		return ErrMaterialInUse
+	}
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrMaterialNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMaterial(row scanner) (*models.Material, error) {
+	rec := new(models.Material)
+	var parent sql.NullString
+	var names []byte
+	if err := row.Scan(&rec.Code, &parent, &names, &rec.Active); err != nil {
+		return nil, err
+	}
+	rec.Parent = parent.String
+	if err := json.Unmarshal(names, &rec.Names); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func materialsSql() string {
+	return "select\n    code, parent, names, active\nfrom\n    materials\norder by\n    code;"
+}
+
+func materialSql() string {
+	return "select\n    code, parent, names, active\nfrom\n    materials\nwhere\n    code = $1;"
+}
+
+func deleteMaterialSql() string {
+	return "delete from materials where code = $1;"
+}
+
+func upsertMaterialSql() string {
+	return "insert into materials\n    (code, parent, names, active)\nvalues\n    ($1, $2, $3, $4)\non conflict (code) do update set\n    parent = excluded.parent,\n    names = excluded.names,\n    active = excluded.active;"
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"aroundHome/app/geo"
+	"aroundHome/app/models"
+	"context"
+	"sort"
+	"sync"
+)
+
+// Memory is a PartnerRepository keeping partners in process memory.
+// It is safe for concurrent use and needs no database.
+type Memory struct {
+	mu       sync.RWMutex
+	partners map[int16]models.Partner
+}
+
+func NewMemory(partners ...*models.Partner) *Memory {
+	r := &Memory{partners: make(map[int16]models.Partner)}
+	for _, p := range partners {
+		r.partners[p.Id] = *p
+	}
+	return r
+}
+
+func (r *Memory) Get(_ context.Context, id int16) (*models.Partner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.partners[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *Memory) Match(_ context.Context, query MatchQuery) ([]*models.PartnerWithDistance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	recs := make([]*models.PartnerWithDistance, 0)
+	for _, p := range r.partners {
+		distance := geo.Distance(query.Lat, query.Lng, float64(p.Lat), float64(p.Lng))
+		if distance >= float64(p.Radius) || !p.HasMaterials(query.Materials) {
+			continue
+		}
+		recs = append(recs, &models.PartnerWithDistance{Partner: p, Distance: float32(distance)})
+	}
+	SortMatches(recs)
+	return recs, nil
+}
+
+func (r *Memory) List(_ context.Context) ([]*models.Partner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	recs := make([]*models.Partner, 0, len(r.partners))
+	for _, p := range r.partners {
+		p := p
+		recs = append(recs, &p)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Id < recs[j].Id })
+	return recs, nil
+}
+
+func (r *Memory) Each(ctx context.Context, fn func(*models.Partner) error) error {
+	recs, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Memory) Upsert(_ context.Context, partners ...*models.Partner) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range partners {
+		r.partners[p.Id] = *p
+	}
+	return nil
+}
+
+// UpsertBatches upserts all partners at once, the batches only matter to the database.
+func (r *Memory) UpsertBatches(ctx context.Context, _ int, partners ...*models.Partner) error {
+	return r.Upsert(ctx, partners...)
+}
+
+func (r *Memory) Create(_ context.Context, partner *models.Partner) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if partner.Id == 0 {
+		for id := range r.partners {
+			if id > partner.Id {
+				partner.Id = id
+			}
+		}
+		partner.Id++
+	}
+	if _, ok := r.partners[partner.Id]; ok {
+		return ErrExists
+	}
+	r.partners[partner.Id] = *partner
+	return nil
+}
+
+func (r *Memory) Update(_ context.Context, partner *models.Partner) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.partners[partner.Id]; !ok {
+		return ErrNotFound
+	}
+	r.partners[partner.Id] = *partner
+	return nil
+}
+
+func (r *Memory) Delete(_ context.Context, id int16) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.partners[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.partners, id)
+	return nil
+}
+
+func (r *Memory) Find(ctx context.Context, filter PartnerFilter) (*PartnerPage, error) {
+	recs, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return filter.Less(recs[i], recs[j]) })
+
+	page := &PartnerPage{Partners: make([]*models.Partner, 0, filter.Limit)}
+	for _, rec := range recs {
+		if !filter.Accepts(rec) || !filter.afterCursor(rec) {
+			continue
+		}
+		if len(page.Partners) == filter.Limit {
+			page.Next = cursorAfter(page.Partners[len(page.Partners)-1], filter.Sort)
+			break
+		}
+		page.Partners = append(page.Partners, rec)
+	}
+	return page, nil
+}
@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+	"sync"
+)
+
+// Postgres is a PartnerRepository backed by the partners table.
+type Postgres struct {
+	db *sql.DB
+
+	// match is the statement of Match, prepared on first use and shared by
+	// all calls, so batches of matches are planned once.
+	mu    sync.Mutex
+	match *sql.Stmt
+}
+
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (r *Postgres) Get(ctx context.Context, id int16) (rec *models.Partner, err error) {
+	ctx, span := startStatement(ctx, "partners.get", partnerSql())
+	defer func() { span.end(rowCount(rec), err) }()
+
+	rec = new(models.Partner)
+	err = scanPartner(r.db.QueryRowContext(ctx, partnerSql(), id), rec)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (r *Postgres) Match(ctx context.Context, query MatchQuery) (recs []*models.PartnerWithDistance, err error) {
+	ctx, span := startStatement(ctx, "partners.match", querySql())
+	defer func() { span.end(len(recs), err) }()
+
+	stmt, err := r.matchStmt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, query.Lat, query.Lng, pq.Array(query.Materials))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recs = make([]*models.PartnerWithDistance, 0)
+	for rows.Next() {
+		rec := new(models.PartnerWithDistance)
+		err := scanPartner(rows, &rec.Partner, &rec.Distance)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, rows.Err()
+}
+
+// matchStmt returns the prepared statement of Match. A failed prepare is
+// retried by the next call.
+func (r *Postgres) matchStmt(ctx context.Context) (*sql.Stmt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.match == nil {
+		stmt, err := r.db.PrepareContext(ctx, querySql())
+		if err != nil {
+			return nil, err
+		}
+		r.match = stmt
+	}
+	return r.match, nil
+}
+
+// Close closes the prepared statement of Match; a later Match prepares it
+// again. The database is left open for its owner to close.
+func (r *Postgres) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.match == nil {
+		return nil
+	}
+	err := r.match.Close()
+	r.match = nil
+	return err
+}
+
+func (r *Postgres) List(ctx context.Context) (recs []*models.Partner, err error) {
+	ctx, span := startStatement(ctx, "partners.list", listSql())
+	defer func() { span.end(len(recs), err) }()
+
+	rows, err := r.db.QueryContext(ctx, listSql())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recs = make([]*models.Partner, 0)
+	for rows.Next() {
+		rec := new(models.Partner)
+		err := scanPartner(rows, rec)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, rows.Err()
+}
+
+func (r *Postgres) Each(ctx context.Context, fn func(*models.Partner) error) (err error) {
+	ctx, span := startStatement(ctx, "partners.each", listSql())
+	read := 0
+	defer func() { span.end(read, err) }()
+
+	rows, err := r.db.QueryContext(ctx, listSql())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rec := new(models.Partner)
+		err := scanPartner(rows, rec)
+		if err != nil {
+			return err
+		}
+		read++
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *Postgres) Upsert(ctx context.Context, partners ...*models.Partner) (err error) {
+	ctx, span := startStatement(ctx, "partners.upsert", upsertSql())
+	defer func() { span.end(written(len(partners), err), err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, upsertSql())
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, p := range partners {
+		_, err := stmt.ExecContext(ctx, p.Id, p.Name, p.Lat, p.Lng, p.Radius, p.Rating, pq.Array(p.Materials()), p.MinSqm, p.MaxSqm)
+		if err != nil {
+			return err
+		}
+	}
+	// keep the id sequence ahead of explicitly inserted ids
+	if _, err := tx.ExecContext(ctx, syncSequenceSql()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Postgres) UpsertBatches(ctx context.Context, batchSize int, partners ...*models.Partner) (err error) {
+	if len(partners) == 0 {
+		return nil
+	}
+	if batchSize <= 0 || batchSize > len(partners) {
+		batchSize = len(partners)
+	}
+	// the statement of a single partner, batches repeat its values
+	ctx, span := startStatement(ctx, "partners.upsert_batches", upsertBatchSql(1))
+	defer func() { span.end(written(len(partners), err), err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(partners); start += batchSize {
+		end := start + batchSize
+		if end > len(partners) {
+			end = len(partners)
+		}
+		batch := lastById(partners[start:end])
+		args := make([]interface{}, 0, len(batch)*9)
+		for _, p := range batch {
+			args = append(args, p.Id, p.Name, p.Lat, p.Lng, p.Radius, p.Rating, pq.Array(p.Materials()), p.MinSqm, p.MaxSqm)
+		}
+		if _, err := tx.ExecContext(ctx, upsertBatchSql(len(batch)), args...); err != nil {
+			return fmt.Errorf("upserting partners %d to %d: %w", start+1, end, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, syncSequenceSql()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lastById keeps the last of the partners with the same id, as a statement
+// cannot upsert a row twice.
+func lastById(partners []*models.Partner) []*models.Partner {
+	last := make(map[int16]int, len(partners))
+	for i, p := range partners {
+		last[p.Id] = i
+	}
+	if len(last) == len(partners) {
+		return partners
+	}
+	kept := make([]*models.Partner, 0, len(last))
+	for i, p := range partners {
+		if last[p.Id] == i {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func (r *Postgres) Create(ctx context.Context, partner *models.Partner) (err error) {
+	ctx, span := startStatement(ctx, "partners.create", insertSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, insertSql(), partner.Id, partner.Name, partner.Lat, partner.Lng, partner.Radius, partner.Rating, pq.Array(partner.Materials()), partner.MinSqm, partner.MaxSqm).
+		Scan(&partner.Id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrExists
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, syncSequenceSql()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Postgres) Update(ctx context.Context, partner *models.Partner) (err error) {
+	ctx, span := startStatement(ctx, "partners.update", updateSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	res, err := r.db.ExecContext(ctx, updateSql(), partner.Id, partner.Name, partner.Lat, partner.Lng, partner.Radius, partner.Rating, pq.Array(partner.Materials()), partner.MinSqm, partner.MaxSqm)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+func (r *Postgres) Delete(ctx context.Context, id int16) (err error) {
+	ctx, span := startStatement(ctx, "partners.delete", deleteSql())
+	defer func() { span.end(written(1, err), err) }()
+
+	res, err := r.db.ExecContext(ctx, deleteSql(), id)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+// affected returns ErrNotFound if res did not affect any partner.
+func affected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// scanPartner scans the partner columns selected by the queries below followed by extra columns.
+func scanPartner(row scanner, rec *models.Partner, extra ...interface{}) error {
+	dest := []interface{}{&rec.Id, &rec.Name, &rec.Lat, &rec.Lng, &rec.Radius, &rec.Rating, &rec.FlooringExperience, &rec.MinSqm, &rec.MaxSqm}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func partnerSql() string {
+	return "select\n    Id, Name, Lat, Lng, Radius, Rating, flooring_experience AS FlooringExperience,\n    coalesce(min_sqm, 0) AS MinSqm, coalesce(max_sqm, 0) AS MaxSqm\nfrom\n    partners\nwhere\n    id = $1;"
+}
+
+// MatchSql returns the statement of Match, taking the latitude, the longitude
+// and the array of materials of the customer, to inspect its query plan.
+func MatchSql() string {
+	return querySql()
+}
+
+// querySql narrows partners down with the indexed coverage box containing the
+// customer and the indexed materials before computing exact distances.
+func querySql() string {
+	return "select\n    Id, Name, Lat, Lng, Radius, Rating, flooring_experience AS FlooringExperience,\n    coalesce(min_sqm, 0) AS MinSqm, coalesce(max_sqm, 0) AS MaxSqm,\n    distance_km($1::double precision, $2::double precision, Lat, Lng) AS Distance\nfrom\n    partners\nwhere\n    coverage @> box(point($2, $1), point($2, $1))\n    AND flooring_experience @> $3\n    AND distance_km($1, $2, Lat, Lng) < Radius\norder by\n    Rating DESC,\n    Distance;"
+}
+
+func listSql() string {
+	return "select\n    Id, Name, Lat, Lng, Radius, Rating, flooring_experience AS FlooringExperience,\n    coalesce(min_sqm, 0) AS MinSqm, coalesce(max_sqm, 0) AS MaxSqm\nfrom\n    partners\norder by\n    id;"
+}
+
+func upsertSql() string {
+	return "insert into partners\n    (id, name, lat, lng, radius, rating, flooring_experience, min_sqm, max_sqm)\nvalues\n    ($1, $2, $3, $4, $5, $6, $7, nullif($8::numeric, 0), nullif($9::numeric, 0))\non conflict (id) do update set\n    name = excluded.name,\n    lat = excluded.lat,\n    lng = excluded.lng,\n    radius = excluded.radius,\n    rating = excluded.rating,\n    flooring_experience = excluded.flooring_experience,\n    min_sqm = excluded.min_sqm,\n    max_sqm = excluded.max_sqm;"
+}
+
+// upsertBatchSql upserts n partners like upsertSql, taking their columns in
+// the order of upsertSql one partner after the other.
+func upsertBatchSql(n int) string {
+	rows := make([]string, n)
+	for i := range rows {
+		p := i * 9
+		rows[i] = fmt.Sprintf("    ($%d::integer, $%d, $%d::numeric, $%d::numeric, $%d::numeric, $%d::double precision, $%d::text[], nullif($%d::numeric, 0), nullif($%d::numeric, 0))",
+			p+1, p+2, p+3, p+4, p+5, p+6, p+7, p+8, p+9)
+	}
+	return "insert into partners\n    (id, name, lat, lng, radius, rating, flooring_experience, min_sqm, max_sqm)\nvalues\n" + strings.Join(rows, ",\n") + "\non conflict (id) do update set\n    name = excluded.name,\n    lat = excluded.lat,\n    lng = excluded.lng,\n    radius = excluded.radius,\n    rating = excluded.rating,\n    flooring_experience = excluded.flooring_experience,\n    min_sqm = excluded.min_sqm,\n    max_sqm = excluded.max_sqm;"
+}
+
+// insertSql takes the next id of the sequence when $1 is 0.
+func insertSql() string {
+	return "insert into partners\n    (id, name, lat, lng, radius, rating, flooring_experience, min_sqm, max_sqm)\nvalues\n    (coalesce(nullif($1::integer, 0), nextval(pg_get_serial_sequence('partners', 'id'))), $2, $3, $4, $5, $6, $7, nullif($8::numeric, 0), nullif($9::numeric, 0))\nreturning\n    id;"
+}
+
+func updateSql() string {
+	return "update partners\nset\n    name = $2,\n    lat = $3,\n    lng = $4,\n    radius = $5,\n    rating = $6,\n    flooring_experience = $7,\n    min_sqm = nullif($8::numeric, 0),\n    max_sqm = nullif($9::numeric, 0)\nwhere\n    id = $1;"
+}
+
+func deleteSql() string {
+	return "delete from partners\nwhere\n    id = $1;"
+}
+
+func syncSequenceSql() string {
+	return "select setval(pg_get_serial_sequence('partners', 'id'), coalesce(max(id), 1)) from partners;"
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"errors"
+	"sort"
+)
+
+// ErrNotFound is returned when a partner with the requested id does not exist.
+var ErrNotFound = errors.New("partner not found")
+
+// ErrExists is returned when creating a partner with the id of an existing one.
+var ErrExists = errors.New("partner already exists")
+
+// MatchQuery describes a customer request to be matched against partners.
+type MatchQuery struct {
+	Lat       float64
+	Lng       float64
+	Materials []string
+}
+
+// PartnerRepository provides access to partner data independent of the storage.
+type PartnerRepository interface {
+	// Get returns the partner with the given id or ErrNotFound.
+	Get(ctx context.Context, id int16) (*models.Partner, error)
+	// Match returns partners experienced with all requested materials whose
+	// operating radius covers the location, ordered by rating and then distance.
+	Match(ctx context.Context, query MatchQuery) ([]*models.PartnerWithDistance, error)
+	// List returns all partners ordered by id.
+	List(ctx context.Context) ([]*models.Partner, error)
+	// Each calls fn for every partner ordered by id without loading all of them
+	// at once. It stops at and returns the first error returned by fn.
+	Each(ctx context.Context, fn func(*models.Partner) error) error
+	// Find returns the page of partners passing the filter, in the order and
+	// after the cursor of the filter.
+	Find(ctx context.Context, filter PartnerFilter) (*PartnerPage, error)
+	// Upsert inserts the partners or replaces existing ones with the same id.
+	Upsert(ctx context.Context, partners ...*models.Partner) error
+	// UpsertBatches upserts the partners like Upsert, batchSize of them per
+	// statement, inside a single transaction: if a batch fails, none of the
+	// partners is written.
+	UpsertBatches(ctx context.Context, batchSize int, partners ...*models.Partner) error
+	// Create inserts a new partner, assigning the next free id if Id is 0.
+	// It returns ErrExists if a partner with the id already exists.
+	Create(ctx context.Context, partner *models.Partner) error
+	// Update replaces the existing partner with the same id or returns ErrNotFound.
+	Update(ctx context.Context, partner *models.Partner) error
+	// Delete removes the partner with the given id or returns ErrNotFound.
+	Delete(ctx context.Context, id int16) error
+}
+
+// SortMatches orders matches the way Match returns them: by rating, best first, then by distance.
+func SortMatches(recs []*models.PartnerWithDistance) {
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Partner.Rating != recs[j].Partner.Rating {
+			return recs[i].Partner.Rating > recs[j].Partner.Rating
+		}
+		return recs[i].Distance < recs[j].Distance
+	})
+}
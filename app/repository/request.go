@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"errors"
+)
+
+// ErrRequestNotFound is returned when a customer request with the requested id does not exist.
+var ErrRequestNotFound = errors.New("customer request not found")
+
+// RequestRepository stores customer requests together with their matches.
+type RequestRepository interface {
+	// Create stores the request and its matches, setting Id, CreatedAt and UpdatedAt.
+	Create(ctx context.Context, request *models.CustomerRequest) error
+	// Get returns the request with the given id including its matches, or ErrRequestNotFound.
+	Get(ctx context.Context, id int64) (*models.CustomerRequest, error)
+}
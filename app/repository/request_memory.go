@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRequests is a RequestRepository keeping customer requests in process memory.
+type MemoryRequests struct {
+	mu       sync.RWMutex
+	lastId   int64
+	requests map[int64]models.CustomerRequest
+}
+
+func NewMemoryRequests() *MemoryRequests {
+	return &MemoryRequests{requests: make(map[int64]models.CustomerRequest)}
+}
+
+func (r *MemoryRequests) Create(_ context.Context, request *models.CustomerRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastId++
+	request.Id = r.lastId
+	request.CreatedAt = time.Now()
+	request.UpdatedAt = request.CreatedAt
+	r.requests[request.Id] = copyRequest(request)
+	return nil
+}
+
+func (r *MemoryRequests) Get(_ context.Context, id int64) (*models.CustomerRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, ErrRequestNotFound
+	}
+	request = copyRequest(&request)
+	return &request, nil
+}
+
+// copyRequest copies request including its matches so callers cannot modify stored requests.
+func copyRequest(request *models.CustomerRequest) models.CustomerRequest {
+	c := *request
+	c.Materials = append([]string(nil), request.Materials...)
+	c.Matches = make([]*models.PartnerWithDistance, len(request.Matches))
+	for i, m := range request.Matches {
+		m := *m
+		c.Matches[i] = &m
+	}
+	return c
+}
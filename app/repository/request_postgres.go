@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"aroundHome/app/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/lib/pq"
+)
+
+// PostgresRequests is a RequestRepository backed by the customer_requests and customer_request_matches tables.
+type PostgresRequests struct {
+	db *sql.DB
+}
+
+func NewPostgresRequests(db *sql.DB) *PostgresRequests {
+	return &PostgresRequests{db: db}
+}
+
+func (r *PostgresRequests) Create(ctx context.Context, request *models.CustomerRequest) (err error) {
+	ctx, span := startStatement(ctx, "requests.create", insertRequestSql())
+	defer func() { span.end(written(1+len(request.Matches), err), err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, insertRequestSql(), pq.Array(request.Materials), request.Lat, request.Lng, request.Sqm, request.Phone).
+		Scan(&request.Id, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, insertRequestMatchSql())
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, m := range request.Matches {
+		factors, err := json.Marshal(m.Factors)
+		if err != nil {
+			return err
+		}
+		partner, err := json.Marshal(m.Partner)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.ExecContext(ctx, request.Id, i+1, m.Partner.Id, m.Distance, m.Score, factors, partner)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRequests) Get(ctx context.Context, id int64) (request *models.CustomerRequest, err error) {
+	ctx, span := startStatement(ctx, "requests.get", requestSql())
+	defer func() {
+		rows := 0
+		if request != nil {
+			rows = 1 + len(request.Matches)
+		}
+		span.end(rows, err)
+	}()
+
+	request = new(models.CustomerRequest)
+	err = r.db.QueryRowContext(ctx, requestSql(), id).
+		Scan(&request.Id, pq.Array(&request.Materials), &request.Lat, &request.Lng, &request.Sqm, &request.Phone, &request.CreatedAt, &request.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, requestMatchesSql(), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	request.Matches = make([]*models.PartnerWithDistance, 0)
+	for rows.Next() {
+		rec := new(models.PartnerWithDistance)
+		var factors, partner []byte
+		if err := rows.Scan(&rec.Distance, &rec.Score, &factors, &partner); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(factors, &rec.Factors); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(partner, &rec.Partner); err != nil {
+			return nil, err
+		}
+		request.Matches = append(request.Matches, rec)
+	}
+	return request, rows.Err()
+}
+
+func insertRequestSql() string {
+	return "insert into customer_requests\n    (materials, lat, lng, sqm, phone)\nvalues\n    ($1, $2, $3, nullif($4::numeric, 0), $5)\nreturning\n    id, created_at, updated_at;"
+}
+
+func insertRequestMatchSql() string {
+	return "insert into customer_request_matches\n    (request_id, rank, partner_id, distance, score, factors, partner)\nvalues\n    ($1, $2, $3, $4, $5, $6, $7);"
+}
+
+func requestSql() string {
+	return "select\n    id, materials, lat, lng, coalesce(sqm, 0), phone, created_at, updated_at\nfrom\n    customer_requests\nwhere\n    id = $1;"
+}
+
+func requestMatchesSql() string {
+	return "select\n    distance, score, factors, partner\nfrom\n    customer_request_matches\nwhere\n    request_id = $1\norder by\n    rank;"
+}
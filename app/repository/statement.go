@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"aroundHome/app/logging"
+	"aroundHome/app/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// rowsKey is the attribute of the rows a statement read or wrote.
+const rowsKey = attribute.Key("db.rows")
+
+var tracer = otel.Tracer("aroundHome/app/repository")
+
+// statement is a traced and logged statement of the data access. Methods
+// running several statements in a transaction are traced as a single
+// statement named after the method, like partners.upsert.
+type statement struct {
+	ctx   context.Context
+	span  trace.Span
+	name  string
+	start time.Time
+}
+
+// startStatement starts the span of the statement named name, like
+// partners.match, as a child of the span in ctx.
+func startStatement(ctx context.Context, name, query string) (context.Context, *statement) {
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(name), semconv.DBStatement(query)))
+	return ctx, &statement{ctx: ctx, span: span, name: name, start: time.Now()}
+}
+
+// expected are the errors caused by the request rather than by the database.
+var expected = []error{sql.ErrNoRows, ErrNotFound, ErrExists, ErrMaterialNotFound, ErrMaterialInUse, ErrRequestNotFound, ErrLeadNotFound}
+
+// end ends the span with the number of rows read or written and the error of
+// the statement and logs it with the request id of its context. Errors caused
+// by the request, like missing rows, are no failure of the statement.
+func (s *statement) end(rows int, err error) {
+	latency := float64(time.Since(s.start).Microseconds()) / 1000
+	s.span.SetAttributes(rowsKey.Int(rows))
+	if err != nil && !isExpected(err) {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+		logging.Error(s.ctx, "statement failed", "statement", s.name, "latency_ms", latency, "error", err)
+	} else {
+		logging.Debug(s.ctx, "statement", "statement", s.name, "rows", rows, "latency_ms", latency)
+	}
+	s.span.End()
+}
+
+// written is the number of rows n a statement wrote, none if it failed with err.
+func written(n int, err error) int {
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func isExpected(err error) bool {
+	for _, target := range expected {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func rowCount(rec *models.Partner) int {
+	if rec == nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,25 @@
+// Package route names the route a request was answered by, like
+// /v1/partners/:id, for the access log, the metrics and the traces.
+package route
+
+import "github.com/gofiber/fiber/v2"
+
+// key is the local holding the route recorded by Set.
+const key = "route"
+
+// Set records path as the route of a request answered before it reached the
+// route, like by the validation against the OpenAPI description.
+func Set(c *fiber.Ctx, path string) {
+	c.Locals(key, path)
+}
+
+// Of returns the route that handled the request, the route recorded by Set,
+// or "" if the path matched no route.
+func Of(c *fiber.Ctx) string {
+	// without a matching route the route of the middleware is left
+	if path := c.Route().Path; path != "/" || c.Path() == "/" {
+		return path
+	}
+	path, _ := c.Locals(key).(string)
+	return path
+}
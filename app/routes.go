@@ -2,25 +2,188 @@ package app
 
 import (
 	"aroundHome/app/controllers"
-	"database/sql"
+	"aroundHome/app/graph"
+	"aroundHome/app/openapi"
 	swagger "github.com/arsmn/fiber-swagger/v2"
 	"github.com/gofiber/fiber/v2"
+	"strings"
 )
 
-func Routes(app *fiber.App, db *sql.DB) {
+// Routes registers the versioned API under /v1 and the deprecated unversioned
+// routes it replaces.
+func Routes(app *fiber.App, services Services) {
+	doc, err := openapi.Load()
+	if err != nil {
+		panic(err)
+	}
+	validator, err := openapi.Validator(doc)
+	if err != nil {
+		panic(err)
+	}
+	app.Use(validator)
+
 	// Routes
 	app.Get("/", controllers.HealthCheck)
+	app.Get("/healthz", controllers.HealthCheck)
+	app.Get("/readyz", func(ctx *fiber.Ctx) error {
+		return controllers.ReadyHandler(ctx, services.Checks)
+	})
+	app.Get("/version", controllers.VersionHandler)
+	app.Get("/metrics", controllers.MetricsHandler)
+	app.Get("/openapi.json", func(ctx *fiber.Ctx) error {
+		return controllers.OpenAPIHandler(ctx, doc)
+	})
 	//app.Get("/swagger/*", swagger.HandlerDefault)     // default
 	app.Get("/swagger/*", swagger.New(swagger.Config{ // custom
-		URL:         "https://raw.githubusercontent.com/sasakocic/aroundhome/master/docs/swagger.json",
+		URL:         "/openapi.json",
 		DeepLinking: false,
 		// Expand ("list") or Collapse ("none") tag groups by default
 		DocExpansion: "none",
 	}))
-	app.Get("/partners/:id", func(ctx *fiber.Ctx) error {
-		return controllers.PartnersHandler(ctx, db)
+
+	v1 := app.Group("/v1")
+	v1.Get("/partners", func(ctx *fiber.Ctx) error {
+		return controllers.ListPartnersV1Handler(ctx, services.Partners)
+	})
+	v1.Get("/partners/export", func(ctx *fiber.Ctx) error {
+		return controllers.ExportV1Handler(ctx, services.Partners)
+	})
+	v1.Get("/partners/:id", func(ctx *fiber.Ctx) error {
+		return controllers.PartnerV1Handler(ctx, services.Partners)
+	})
+	v1.Post("/partners", func(ctx *fiber.Ctx) error {
+		return controllers.CreatePartnerV1Handler(ctx, services.Partners, services.Materials)
+	})
+	v1.Put("/partners/:id", func(ctx *fiber.Ctx) error {
+		return controllers.UpdatePartnerV1Handler(ctx, services.Partners, services.Materials)
+	})
+	v1.Patch("/partners/:id", func(ctx *fiber.Ctx) error {
+		return controllers.PatchPartnerV1Handler(ctx, services.Partners, services.Materials)
+	})
+	v1.Delete("/partners/:id", func(ctx *fiber.Ctx) error {
+		return controllers.DeletePartnerHandler(ctx, services.Partners)
+	})
+	v1.Post("/partners/import", func(ctx *fiber.Ctx) error {
+		return controllers.ImportV1Handler(ctx, services.Partners, services.Materials)
+	})
+	v1.Get("/query", func(ctx *fiber.Ctx) error {
+		return controllers.QueryV1Handler(ctx, services.Matcher)
+	})
+	v1.Post("/query/batch", func(ctx *fiber.Ctx) error {
+		return controllers.QueryBatchV1Handler(ctx, services.Matcher)
+	})
+	v1.Post("/requests", func(ctx *fiber.Ctx) error {
+		return controllers.CreateRequestV1Handler(ctx, services.Matcher, services.Requests, services.Dispatcher)
+	})
+	v1.Get("/requests/:id", func(ctx *fiber.Ctx) error {
+		return controllers.GetRequestV1Handler(ctx, services.Requests)
+	})
+	v1.Get("/requests/:id/leads", func(ctx *fiber.Ctx) error {
+		return controllers.RequestLeadsV1Handler(ctx, services.Requests, services.Leads)
+	})
+	v1.Get("/leads/:id", func(ctx *fiber.Ctx) error {
+		return controllers.LeadV1Handler(ctx, services.Leads)
+	})
+	v1.Post("/leads/:id/view", func(ctx *fiber.Ctx) error {
+		return controllers.ViewLeadV1Handler(ctx, services.Dispatcher)
+	})
+	v1.Post("/leads/:id/accept", func(ctx *fiber.Ctx) error {
+		return controllers.AcceptLeadV1Handler(ctx, services.Dispatcher)
+	})
+	v1.Post("/leads/:id/decline", func(ctx *fiber.Ctx) error {
+		return controllers.DeclineLeadV1Handler(ctx, services.Dispatcher)
+	})
+	v1.Get("/admin/materials", func(ctx *fiber.Ctx) error {
+		return controllers.ListMaterialsV1Handler(ctx, services.Materials)
+	})
+	v1.Get("/admin/materials/:code", func(ctx *fiber.Ctx) error {
+		return controllers.GetMaterialV1Handler(ctx, services.Materials)
+	})
+	v1.Put("/admin/materials/:code", func(ctx *fiber.Ctx) error {
+		return controllers.PutMaterialV1Handler(ctx, services.Materials)
+	})
+	v1.Delete("/admin/materials/:code", func(ctx *fiber.Ctx) error {
+		return controllers.DeleteMaterialHandler(ctx, services.Materials)
+	})
+
+	schema := graph.New(services.Partners, services.Matcher)
+	app.Get("/graphql", func(ctx *fiber.Ctx) error {
+		return controllers.GraphQLGetHandler(ctx, schema)
+	})
+	app.Post("/graphql", func(ctx *fiber.Ctx) error {
+		return controllers.GraphQLHandler(ctx, schema)
+	})
+
+	// Unversioned routes of the first API, kept until clients moved to /v1
+	app.Get("/partners", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.ListPartnersHandler(ctx, services.Partners)
+	})
+	app.Get("/partners/export", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.ExportHandler(ctx, services.Partners)
+	})
+	app.Get("/partners/:id", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.PartnersHandler(ctx, services.Partners)
+	})
+	app.Post("/partners", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.CreatePartnerHandler(ctx, services.Partners, services.Materials)
+	})
+	app.Put("/partners/:id", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.UpdatePartnerHandler(ctx, services.Partners, services.Materials)
+	})
+	app.Patch("/partners/:id", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.PatchPartnerHandler(ctx, services.Partners, services.Materials)
+	})
+	app.Delete("/partners/:id", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.DeletePartnerHandler(ctx, services.Partners)
+	})
+	app.Post("/partners/import", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.ImportHandler(ctx, services.Partners, services.Materials)
+	})
+	app.Get("/query", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.QueryHandler(ctx, services.Matcher)
+	})
+	app.Post("/requests", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.CreateRequestHandler(ctx, services.Matcher, services.Requests, services.Dispatcher)
 	})
-	app.Get("/query/*", func(ctx *fiber.Ctx) error {
-		return controllers.QueryHandler(ctx, db)
+	app.Get("/requests/:id", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.GetRequestHandler(ctx, services.Requests)
 	})
+	app.Get("/requests/:id/leads", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.RequestLeadsHandler(ctx, services.Requests, services.Leads)
+	})
+	app.Get("/leads/:id", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.LeadHandler(ctx, services.Leads)
+	})
+	app.Post("/leads/:id/view", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.ViewLeadHandler(ctx, services.Dispatcher)
+	})
+	app.Post("/leads/:id/accept", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.AcceptLeadHandler(ctx, services.Dispatcher)
+	})
+	app.Post("/leads/:id/decline", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.DeclineLeadHandler(ctx, services.Dispatcher)
+	})
+
+	admin := app.Group("/admin")
+	admin.Get("/materials", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.ListMaterialsHandler(ctx, services.Materials)
+	})
+	admin.Get("/materials/:code", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.GetMaterialHandler(ctx, services.Materials)
+	})
+	admin.Put("/materials/:code", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.PutMaterialHandler(ctx, services.Materials)
+	})
+	admin.Delete("/materials/:code", deprecated, func(ctx *fiber.Ctx) error {
+		return controllers.DeleteMaterialHandler(ctx, services.Materials)
+	})
+}
+
+// deprecated marks a response of an unversioned route, pointing clients at the
+// same route under /v1.
+func deprecated(ctx *fiber.Ctx) error {
+	ctx.Set("Deprecation", "true")
+	successor := "/v1" + strings.TrimSuffix(ctx.Path(), "/")
+	ctx.Set(fiber.HeaderLink, "<"+successor+">; rel=\"successor-version\"")
+	return ctx.Next()
 }
@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: aroundhome/v1/partners.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Partners_MatchPartners_FullMethodName = "/aroundhome.v1.Partners/MatchPartners"
+	Partners_GetPartner_FullMethodName    = "/aroundhome.v1.Partners/GetPartner"
+	Partners_ListPartners_FullMethodName  = "/aroundhome.v1.Partners/ListPartners"
+)
+
+// PartnersClient is the client API for Partners service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PartnersClient interface {
+	// MatchPartners returns the partners covering the customer and experienced
+	// with every material, best match first. Nothing is stored.
+	MatchPartners(ctx context.Context, in *MatchPartnersRequest, opts ...grpc.CallOption) (*MatchPartnersResponse, error)
+	// GetPartner returns the partner with the id, NOT_FOUND if there is none.
+	GetPartner(ctx context.Context, in *GetPartnerRequest, opts ...grpc.CallOption) (*Partner, error)
+	// ListPartners streams all partners passing the filters in the given order.
+	ListPartners(ctx context.Context, in *ListPartnersRequest, opts ...grpc.CallOption) (Partners_ListPartnersClient, error)
+}
+
+type partnersClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPartnersClient(cc grpc.ClientConnInterface) PartnersClient {
+	return &partnersClient{cc}
+}
+
+func (c *partnersClient) MatchPartners(ctx context.Context, in *MatchPartnersRequest, opts ...grpc.CallOption) (*MatchPartnersResponse, error) {
+	out := new(MatchPartnersResponse)
+	err := c.cc.Invoke(ctx, Partners_MatchPartners_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partnersClient) GetPartner(ctx context.Context, in *GetPartnerRequest, opts ...grpc.CallOption) (*Partner, error) {
+	out := new(Partner)
+	err := c.cc.Invoke(ctx, Partners_GetPartner_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partnersClient) ListPartners(ctx context.Context, in *ListPartnersRequest, opts ...grpc.CallOption) (Partners_ListPartnersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Partners_ServiceDesc.Streams[0], Partners_ListPartners_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &partnersListPartnersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Partners_ListPartnersClient interface {
+	Recv() (*Partner, error)
+	grpc.ClientStream
+}
+
+type partnersListPartnersClient struct {
+	grpc.ClientStream
+}
+
+func (x *partnersListPartnersClient) Recv() (*Partner, error) {
+	m := new(Partner)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PartnersServer is the server API for Partners service.
+// All implementations must embed UnimplementedPartnersServer
+// for forward compatibility
+type PartnersServer interface {
+	// MatchPartners returns the partners covering the customer and experienced
+	// with every material, best match first. Nothing is stored.
+	MatchPartners(context.Context, *MatchPartnersRequest) (*MatchPartnersResponse, error)
+	// GetPartner returns the partner with the id, NOT_FOUND if there is none.
+	GetPartner(context.Context, *GetPartnerRequest) (*Partner, error)
+	// ListPartners streams all partners passing the filters in the given order.
+	ListPartners(*ListPartnersRequest, Partners_ListPartnersServer) error
+	mustEmbedUnimplementedPartnersServer()
+}
+
+// UnimplementedPartnersServer must be embedded to have forward compatible implementations.
+type UnimplementedPartnersServer struct {
+}
+
+func (UnimplementedPartnersServer) MatchPartners(context.Context, *MatchPartnersRequest) (*MatchPartnersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatchPartners not implemented")
+}
+func (UnimplementedPartnersServer) GetPartner(context.Context, *GetPartnerRequest) (*Partner, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPartner not implemented")
+}
+func (UnimplementedPartnersServer) ListPartners(*ListPartnersRequest, Partners_ListPartnersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPartners not implemented")
+}
+func (UnimplementedPartnersServer) mustEmbedUnimplementedPartnersServer() {}
+
+// UnsafePartnersServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PartnersServer will
+// result in compilation errors.
+type UnsafePartnersServer interface {
+	mustEmbedUnimplementedPartnersServer()
+}
+
+func RegisterPartnersServer(s grpc.ServiceRegistrar, srv PartnersServer) {
+	s.RegisterService(&Partners_ServiceDesc, srv)
+}
+
+func _Partners_MatchPartners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatchPartnersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartnersServer).MatchPartners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Partners_MatchPartners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartnersServer).MatchPartners(ctx, req.(*MatchPartnersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Partners_GetPartner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPartnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartnersServer).GetPartner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Partners_GetPartner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartnersServer).GetPartner(ctx, req.(*GetPartnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Partners_ListPartners_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPartnersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PartnersServer).ListPartners(m, &partnersListPartnersServer{stream})
+}
+
+type Partners_ListPartnersServer interface {
+	Send(*Partner) error
+	grpc.ServerStream
+}
+
+type partnersListPartnersServer struct {
+	grpc.ServerStream
+}
+
+func (x *partnersListPartnersServer) Send(m *Partner) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Partners_ServiceDesc is the grpc.ServiceDesc for Partners service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Partners_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aroundhome.v1.Partners",
+	HandlerType: (*PartnersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MatchPartners",
+			Handler:    _Partners_MatchPartners_Handler,
+		},
+		{
+			MethodName: "GetPartner",
+			Handler:    _Partners_GetPartner_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPartners",
+			Handler:       _Partners_ListPartners_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "aroundhome/v1/partners.proto",
+}
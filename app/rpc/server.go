@@ -0,0 +1,152 @@
+// Package rpc serves the matching and the partner lookup over gRPC for internal
+// services. It shares the repository and the matching.Service with the HTTP API.
+package rpc
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=aroundHome --go-grpc_out=../.. --go-grpc_opt=module=aroundHome aroundhome/v1/partners.proto
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/dto"
+	"aroundHome/app/logging"
+	"aroundHome/app/matching"
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"aroundHome/app/rpc/pb"
+	"context"
+	"math"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// listPageSize is the number of partners ListPartners reads from the repository at once.
+const listPageSize = 200
+
+// Server implements pb.PartnersServer.
+type Server struct {
+	pb.UnimplementedPartnersServer
+	Partners repository.PartnerRepository
+	Matcher  *matching.Service
+}
+
+// NewServer returns a gRPC server with the Partners service wired to services
+// and the health and reflection services registered.
+func NewServer(services app.Services, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	pb.RegisterPartnersServer(s, &Server{Partners: services.Partners, Matcher: services.Matcher})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.Partners_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+	reflection.Register(s)
+	return s
+}
+
+func (s *Server) MatchPartners(ctx context.Context, req *pb.MatchPartnersRequest) (*pb.MatchPartnersResponse, error) {
+	recs, err := s.Matcher.Match(ctx, matching.Request{Lat: req.Lat, Lng: req.Lng, Materials: req.Materials, Sqm: req.Sqm})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	res := &pb.MatchPartnersResponse{Matches: make([]*pb.Match, len(recs))}
+	for i, rec := range recs {
+		m := dto.FromMatch(rec)
+		res.Matches[i] = &pb.Match{
+			Partner:    fromPartner(m.Partner),
+			DistanceKm: m.Distance.Value,
+			Score:      m.Score,
+			Factors:    m.Factors,
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) GetPartner(ctx context.Context, req *pb.GetPartnerRequest) (*pb.Partner, error) {
+	if req.Id < 0 || req.Id > math.MaxInt16 {
+		return nil, status.Errorf(codes.NotFound, "%v", repository.ErrNotFound)
+	}
+	rec, err := s.Partners.Get(ctx, int16(req.Id))
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return fromPartner(dto.FromPartner(rec)), nil
+}
+
+func (s *Server) ListPartners(req *pb.ListPartnersRequest, stream pb.Partners_ListPartnersServer) error {
+	filter, err := partnerFilter(req)
+	if err != nil {
+		return err
+	}
+	// pages are read with the keyset cursor, so the stream holds no
+	// transaction open while the client is slow to receive
+	for {
+		page, err := s.Partners.Find(stream.Context(), *filter)
+		if err != nil {
+			return statusError(stream.Context(), err)
+		}
+		for _, rec := range page.Partners {
+			if err := stream.Send(fromPartner(dto.FromPartner(rec))); err != nil {
+				return err
+			}
+		}
+		if page.Next == nil {
+			return nil
+		}
+		filter.After = page.Next
+	}
+}
+
+func partnerFilter(req *pb.ListPartnersRequest) (*repository.PartnerFilter, error) {
+	filter := &repository.PartnerFilter{
+		Materials:    req.Materials,
+		AnyMaterials: req.AnyMaterials,
+		NamePrefix:   req.NamePrefix,
+		Sort:         repository.SortId,
+		Limit:        listPageSize,
+	}
+	if req.MinRating != nil {
+		r := float32(*req.MinRating)
+		filter.MinRating = &r
+	}
+	if req.MaxRating != nil {
+		r := float32(*req.MaxRating)
+		filter.MaxRating = &r
+	}
+	if req.Sort != "" {
+		sort, err := repository.ParseSort(req.Sort)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "sort: %v", err)
+		}
+		filter.Sort = sort
+	}
+	return filter, nil
+}
+
+func fromPartner(p dto.Partner) *pb.Partner {
+	return &pb.Partner{
+		Id:        int32(p.Id),
+		Name:      p.Name,
+		Lat:       p.Lat,
+		Lng:       p.Lng,
+		RadiusKm:  p.RadiusKm,
+		Rating:    p.Rating,
+		Materials: p.Materials,
+		MinSqm:    p.MinSqm,
+		MaxSqm:    p.MaxSqm,
+	}
+}
+
+// statusError classifies err like the HTTP API does. Errors not caused by the
+// request are logged and answered INTERNAL without details.
+func statusError(ctx context.Context, err error) error {
+	p := problem.From(err)
+	code, ok := p.Code()
+	if !ok {
+		logging.Error(ctx, "grpc call failed", "error", err)
+		return status.Error(code.GRPC, "internal error")
+	}
+	return status.Error(code.GRPC, p.Detail)
+}
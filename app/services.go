@@ -0,0 +1,22 @@
+package app
+
+import (
+	"aroundHome/app/health"
+	"aroundHome/app/leads"
+	"aroundHome/app/matching"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+)
+
+// Services are the dependencies the routes are wired with.
+type Services struct {
+	Partners  repository.PartnerRepository
+	Materials *taxonomy.Store
+	Matcher   *matching.Service
+	Requests  repository.RequestRepository
+	Leads     repository.LeadRepository
+	// Dispatcher offers stored requests to partners, sharing Requests and Leads.
+	Dispatcher *leads.Dispatcher
+	// Checks decide whether the server is ready, none means always ready.
+	Checks []health.Check
+}
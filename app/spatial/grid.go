@@ -0,0 +1,121 @@
+// Package spatial matches customer requests against partners held in an
+// in-process grid index instead of scanning the partners table.
+package spatial
+
+import (
+	"aroundHome/app/geo"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"math"
+)
+
+// DefaultCellSize is the edge length of grid cells in degrees. Operating radii
+// of up to 150 km make a partner cover only a handful of one-degree cells.
+const DefaultCellSize = 1.0
+
+type cell struct {
+	row int
+	col int
+}
+
+type entry struct {
+	partner   models.Partner
+	materials map[string]bool
+}
+
+// Grid is an immutable index of partners keyed by the cells their operating
+// circle overlaps, so a lookup only needs to check the partners of one cell.
+type Grid struct {
+	size  float64
+	cols  int
+	cells map[cell][]*entry
+	count int
+}
+
+// NewGrid indexes partners in cells of cellSize degrees.
+func NewGrid(partners []*models.Partner, cellSize float64) *Grid {
+	g := &Grid{
+		size:  cellSize,
+		cols:  int(math.Ceil(360 / cellSize)),
+		cells: make(map[cell][]*entry),
+		count: len(partners),
+	}
+	for _, p := range partners {
+		e := &entry{partner: *p, materials: make(map[string]bool)}
+		for _, v := range p.Materials() {
+			e.materials[v] = true
+		}
+		box := geo.BoundingBox(float64(p.Lat), float64(p.Lng), float64(p.Radius))
+		for row := g.row(box.MinLat); row <= g.row(box.MaxLat); row++ {
+			for _, col := range g.colRange(box) {
+				c := cell{row: row, col: col}
+				g.cells[c] = append(g.cells[c], e)
+			}
+		}
+	}
+	return g
+}
+
+// Len returns the number of indexed partners.
+func (g *Grid) Len() int {
+	return g.count
+}
+
+// Match returns the partners experienced with all requested materials whose
+// operating radius covers the location, sorted like repository.PartnerRepository.Match.
+func (g *Grid) Match(query repository.MatchQuery) []*models.PartnerWithDistance {
+	recs := make([]*models.PartnerWithDistance, 0)
+	for _, e := range g.cells[cell{row: g.row(query.Lat), col: g.col(query.Lng)}] {
+		if !e.covers(query.Materials) {
+			continue
+		}
+		distance := geo.Distance(query.Lat, query.Lng, float64(e.partner.Lat), float64(e.partner.Lng))
+		if distance >= float64(e.partner.Radius) {
+			continue
+		}
+		recs = append(recs, &models.PartnerWithDistance{Partner: e.partner, Distance: float32(distance)})
+	}
+	repository.SortMatches(recs)
+	return recs
+}
+
+func (e *entry) covers(materials []string) bool {
+	for _, v := range materials {
+		if !e.materials[v] {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Grid) row(lat float64) int {
+	return int(math.Floor((lat + 90) / g.size))
+}
+
+func (g *Grid) col(lng float64) int {
+	col := int(math.Floor((lng + 180) / g.size))
+	// longitude 180 is the same meridian as -180
+	return col % g.cols
+}
+
+// colRange returns the columns overlapped by the box, wrapping at the antimeridian.
+func (g *Grid) colRange(box geo.Box) []int {
+	cols := make([]int, 0)
+	first, last := g.col(box.MinLng), g.col(box.MaxLng)
+	if box.MinLng == -180 && box.MaxLng == 180 {
+		first, last = 0, g.cols-1
+	}
+	if !box.CrossesAntimeridian() && first <= last {
+		for col := first; col <= last; col++ {
+			cols = append(cols, col)
+		}
+		return cols
+	}
+	for col := first; col < g.cols; col++ {
+		cols = append(cols, col)
+	}
+	for col := 0; col <= last; col++ {
+		cols = append(cols, col)
+	}
+	return cols
+}
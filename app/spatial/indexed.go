@@ -0,0 +1,123 @@
+package spatial
+
+import (
+	"aroundHome/app/logging"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Indexed is a repository.PartnerRepository answering Match from a Grid built
+// from the wrapped repository. All other methods are passed through. The grid
+// is rebuilt after every change made through it and by Run to pick up changes
+// made elsewhere, or changes whose rebuild failed.
+type Indexed struct {
+	repository.PartnerRepository
+	cellSize float64
+	grid     atomic.Pointer[Grid]
+	// refreshed is the time of the last successful refresh in Unix nanoseconds.
+	refreshed atomic.Int64
+}
+
+// NewIndexed wraps partners and loads the initial index.
+func NewIndexed(ctx context.Context, partners repository.PartnerRepository, cellSize float64) (*Indexed, error) {
+	r := &Indexed{PartnerRepository: partners, cellSize: cellSize}
+	if err := r.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Indexed) Match(_ context.Context, query repository.MatchQuery) ([]*models.PartnerWithDistance, error) {
+	return r.grid.Load().Match(query), nil
+}
+
+func (r *Indexed) Upsert(ctx context.Context, partners ...*models.Partner) error {
+	if err := r.PartnerRepository.Upsert(ctx, partners...); err != nil {
+		return err
+	}
+	r.refreshAfterChange(ctx)
+	return nil
+}
+
+func (r *Indexed) UpsertBatches(ctx context.Context, batchSize int, partners ...*models.Partner) error {
+	if err := r.PartnerRepository.UpsertBatches(ctx, batchSize, partners...); err != nil {
+		return err
+	}
+	r.refreshAfterChange(ctx)
+	return nil
+}
+
+func (r *Indexed) Create(ctx context.Context, partner *models.Partner) error {
+	if err := r.PartnerRepository.Create(ctx, partner); err != nil {
+		return err
+	}
+	r.refreshAfterChange(ctx)
+	return nil
+}
+
+func (r *Indexed) Update(ctx context.Context, partner *models.Partner) error {
+	if err := r.PartnerRepository.Update(ctx, partner); err != nil {
+		return err
+	}
+	r.refreshAfterChange(ctx)
+	return nil
+}
+
+func (r *Indexed) Delete(ctx context.Context, id int16) error {
+	if err := r.PartnerRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.refreshAfterChange(ctx)
+	return nil
+}
+
+// Refreshed returns the time the index was last built successfully, the zero
+// time if it never was.
+func (r *Indexed) Refreshed() time.Time {
+	n := r.refreshed.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Refresh rebuilds the index from the wrapped repository. Matches keep using
+// the previous index until the new one is complete.
+func (r *Indexed) Refresh(ctx context.Context) error {
+	partners, err := r.PartnerRepository.List(ctx)
+	if err != nil {
+		return err
+	}
+	r.grid.Store(NewGrid(partners, r.cellSize))
+	r.refreshed.Store(time.Now().UnixNano())
+	return nil
+}
+
+// refreshAfterChange rebuilds the index after a change made through it. The
+// change is committed already, so a failed refresh is logged instead of
+// failing it; the previous index stays in use until Run refreshes it.
+func (r *Indexed) refreshAfterChange(ctx context.Context) {
+	if err := r.Refresh(ctx); err != nil {
+		logging.Error(ctx, "refreshing partner index after a change failed", "error", err)
+	}
+}
+
+// Run refreshes the index every interval until ctx is done. Failed refreshes
+// are logged and the previous index stays in use.
+func (r *Indexed) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				logging.Error(ctx, "refreshing partner index failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Package taxonomy decides which flooring materials can be requested by
+// customers and offered by partners, based on the materials table.
+package taxonomy
+
+import (
+	"aroundHome/app/models"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Catalog is an immutable snapshot of the material taxonomy.
+type Catalog struct {
+	materials map[string]*models.Material
+	children  map[string][]string
+}
+
+func NewCatalog(materials []*models.Material) *Catalog {
+	c := &Catalog{
+		materials: make(map[string]*models.Material),
+		children:  make(map[string][]string),
+	}
+	for _, m := range materials {
+		c.materials[m.Code] = m
+		if m.Parent != "" {
+			c.children[m.Parent] = append(c.children[m.Parent], m.Code)
+		}
+	}
+	for _, codes := range c.children {
+		sort.Strings(codes)
+	}
+	return c
+}
+
+// Get returns the material with the given code.
+func (c *Catalog) Get(code string) (*models.Material, bool) {
+	m, ok := c.materials[code]
+	return m, ok
+}
+
+// IsCategory reports whether other materials belong to the material with the given code.
+func (c *Catalog) IsCategory(code string) bool {
+	return len(c.children[code]) > 0
+}
+
+// Codes returns the sorted codes of all active materials that are not categories.
+func (c *Catalog) Codes() []string {
+	codes := make([]string, 0, len(c.materials))
+	for code, m := range c.materials {
+		if m.Active && !c.IsCategory(code) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Check returns an error unless code names an active material that is not a category.
+func (c *Catalog) Check(code string) error {
+	m, ok := c.materials[code]
+	if !ok {
+		return fmt.Errorf("unknown material %q, allowed are %s", code, strings.Join(c.Codes(), ", "))
+	}
+	if !m.Active {
+		return fmt.Errorf("material %q is no longer offered", code)
+	}
+	if c.IsCategory(code) {
+		return fmt.Errorf("%q is a category, use one of %s", code, strings.Join(c.children[code], ", "))
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package taxonomy
+
+import "aroundHome/app/models"
+
+// Defaults returns the materials the materials table is created with.
+func Defaults() []*models.Material {
+	return []*models.Material{
+		{Code: "hard_floors", Names: map[string]string{"en": "Hard floors", "de": "Hartböden"}, Active: true},
+		{Code: "soft_floors", Names: map[string]string{"en": "Soft floors", "de": "Weichböden"}, Active: true},
+		{Code: "carpet", Parent: "soft_floors", Names: map[string]string{"en": "Carpet", "de": "Teppich"}, Active: true},
+		{Code: "cement", Parent: "hard_floors", Names: map[string]string{"en": "Cement", "de": "Zement"}, Active: true},
+		{Code: "tiles", Parent: "hard_floors", Names: map[string]string{"en": "Tiles", "de": "Fliesen"}, Active: true},
+		{Code: "wood", Parent: "hard_floors", Names: map[string]string{"en": "Wood", "de": "Holz"}, Active: true},
+	}
+}
@@ -0,0 +1,59 @@
+package taxonomy
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a loaded Catalog is used before the materials are read again.
+const DefaultTTL = time.Minute
+
+// Store is a repository.MaterialRepository caching the Catalog built from the
+// wrapped repository. Writes through the Store invalidate the cache at once,
+// changes made by other instances are picked up after the TTL.
+type Store struct {
+	repository.MaterialRepository
+	ttl      time.Duration
+	mu       sync.Mutex
+	catalog  *Catalog
+	loadedAt time.Time
+}
+
+func NewStore(materials repository.MaterialRepository, ttl time.Duration) *Store {
+	return &Store{MaterialRepository: materials, ttl: ttl}
+}
+
+// Catalog returns the current material taxonomy.
+func (s *Store) Catalog(ctx context.Context) (*Catalog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.catalog != nil && time.Since(s.loadedAt) < s.ttl {
+		return s.catalog, nil
+	}
+	materials, err := s.MaterialRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.catalog = NewCatalog(materials)
+	s.loadedAt = time.Now()
+	return s.catalog, nil
+}
+
+func (s *Store) Upsert(ctx context.Context, material *models.Material) error {
+	defer s.invalidate()
+	return s.MaterialRepository.Upsert(ctx, material)
+}
+
+func (s *Store) Delete(ctx context.Context, code string) error {
+	defer s.invalidate()
+	return s.MaterialRepository.Delete(ctx, code)
+}
+
+func (s *Store) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catalog = nil
+}
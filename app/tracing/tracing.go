@@ -0,0 +1,125 @@
+// Package tracing records OpenTelemetry traces of the requests to the server.
+// Every request gets a server span, continuing the trace of an incoming W3C
+// traceparent header, and the repositories add a span per SQL statement.
+package tracing
+
+import (
+	"aroundHome/app/route"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters select where spans are sent to.
+const (
+	// None records no spans.
+	None = "none"
+	// Stdout writes the spans to the standard output, for local debugging.
+	Stdout = "stdout"
+	// OTLP sends the spans to a collector over HTTP, by default to
+	// localhost:4318, configured by the OTEL_EXPORTER_OTLP_* variables.
+	OTLP = "otlp"
+)
+
+// serviceName names the spans of the server in the traces.
+const serviceName = "aroundhome"
+
+// Setup installs a tracer provider sending the spans to exporter and the W3C
+// trace context propagation. The returned function flushes the pending spans.
+func Setup(ctx context.Context, exporter string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case "", None:
+		return func(context.Context) error { return nil }, nil
+	case Stdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case OTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, use none, stdout or otlp", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Middleware starts the server span of every request and passes it to the
+// handlers in the user context. The span is named after the route that handled
+// the request, like GET /v1/partners/:id. It expects the errors of the handlers
+// to be answered after it, by problem.Middleware, to record their status.
+func Middleware() fiber.Handler {
+	tracer := otel.Tracer("aroundHome/app/tracing")
+	return func(c *fiber.Ctx) error {
+		// values of the context point into buffers reused by the next request,
+		// spans are exported later
+		method := strings.Clone(c.Method())
+		// the query string is left out, it may hold personal data like the phone number
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{&c.Request().Header})
+		ctx, span := tracer.Start(ctx, "HTTP "+method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPMethod(method), semconv.HTTPTarget(strings.Clone(c.Path()))))
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		if err := c.Next(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+
+		if path := route.Of(c); path != "" {
+			span.SetName(method + " " + path)
+			span.SetAttributes(semconv.HTTPRoute(path))
+		}
+		status := c.Response().StatusCode()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return nil
+	}
+}
+
+// headerCarrier reads and writes propagated fields as request headers.
+type headerCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+func (h headerCarrier) Get(key string) string {
+	return string(h.header.Peek(key))
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/taxonomy"
+	"regexp"
+	"strings"
+)
+
+var materialCode = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Material checks a material before it is stored in the catalog and returns Errors if it is invalid.
+// Categories only have one level, so a parent must not have a parent itself.
+func Material(m *models.Material, catalog *taxonomy.Catalog) error {
+	var errs Errors
+	if !materialCode.MatchString(m.Code) {
+		errs.Add("code", "must consist of lowercase letters, digits and underscores")
+	}
+	if len(m.Names) == 0 {
+		errs.Add("names", "must contain the name for at least one locale")
+	}
+	for locale, name := range m.Names {
+		if locale == "" || strings.TrimSpace(name) == "" {
+			errs.Add("names", "locale and name must not be empty")
+			break
+		}
+	}
+	if m.Parent != "" {
+		parent, ok := catalog.Get(m.Parent)
+		switch {
+		case m.Parent == m.Code:
+			errs.Add("parent", "must not be the material itself")
+		case !ok:
+			errs.Add("parent", "unknown material %q", m.Parent)
+		case parent.Parent != "":
+			errs.Add("parent", "%q belongs to %q itself, categories cannot be nested", m.Parent, parent.Parent)
+		case catalog.IsCategory(m.Code):
+			errs.Add("parent", "%q is a category and cannot have a parent", m.Code)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
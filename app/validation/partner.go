@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/taxonomy"
+	"fmt"
+	"strings"
+)
+
+// FieldError describes why the value of a single field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors collects all field errors of a value so they can be reported at once.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, v := range e {
+		if v.Field == "" {
+			messages[i] = v.Message
+		} else {
+			messages[i] = v.Field + ": " + v.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add appends an error for the given field.
+func (e *Errors) Add(field, format string, args ...interface{}) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Partner checks the constraints models.Partner documents, with materials taken
+// from the catalog, and returns Errors if any is violated.
+func Partner(p *models.Partner, catalog *taxonomy.Catalog) error {
+	var errs Errors
+	if strings.TrimSpace(p.Name) == "" {
+		errs.Add("name", "must not be empty")
+	}
+	if p.Lat < -90 || p.Lat > 90 {
+		errs.Add("lat", "must be between -90 and 90")
+	}
+	if p.Lng < -180 || p.Lng > 180 {
+		errs.Add("lng", "must be between -180 and 180")
+	}
+	if p.Radius <= 0 {
+		errs.Add("radius", "must be positive")
+	}
+	if p.Rating < 0 || p.Rating > 10 {
+		errs.Add("rating", "must be between 0 and 10")
+	}
+	if p.MinSqm < 0 {
+		errs.Add("min_sqm", "must not be negative")
+	}
+	if p.MaxSqm < 0 {
+		errs.Add("max_sqm", "must not be negative")
+	}
+	if p.MaxSqm > 0 && p.MinSqm > p.MaxSqm {
+		errs.Add("max_sqm", "must not be less than min_sqm")
+	}
+	for _, v := range p.Materials() {
+		if err := catalog.Check(v); err != nil {
+			errs.Add("flooring_experience", "%v", err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
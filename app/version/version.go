@@ -0,0 +1,57 @@
+// Package version describes the build of the binary. Version and Commit are
+// set when building, like
+//
+//	go build -ldflags "-X aroundHome/app/version.Version=1.4.0 -X aroundHome/app/version.Commit=$(git rev-parse HEAD)"
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// Version is the release of the binary, dev for builds without -ldflags.
+	Version = "dev"
+	// Commit is the git commit the binary is built from. Without -ldflags it
+	// is taken from the version control information embedded by go build.
+	Commit = ""
+)
+
+// Info is the build of the binary.
+type Info struct {
+	Version   string `json:"version" example:"1.4.0"`
+	Commit    string `json:"commit" example:"c906fcb"`
+	GoVersion string `json:"go_version" example:"go1.19"`
+}
+
+// Get returns the build of the running binary. The commit is unknown when
+// neither -ldflags nor go build set it, e.g. for go run.
+func Get() Info {
+	return Info{Version: Version, Commit: commit(), GoVersion: runtime.Version()}
+}
+
+func commit() string {
+	if Commit != "" {
+		return Commit
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "unknown"
+	}
+	if modified {
+		return revision + "-dirty"
+	}
+	return revision
+}
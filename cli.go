@@ -0,0 +1,62 @@
+package main
+
+import (
+	"aroundHome/app/logging"
+	"aroundHome/app/version"
+	"github.com/urfave/cli/v2"
+	"os"
+)
+
+// newCLI returns the command line of the binary. Every command reads the
+// database from the PG_* environment variables, see app.DataSourceName, and
+// logs JSON lines of the level of --log-level to stderr.
+func newCLI() *cli.App {
+	return &cli.App{
+		Name:    "aroundhome",
+		Usage:   "match customers with flooring partners",
+		Version: version.Version,
+		Description: "Without a command the web and gRPC servers are started like with serve, " +
+			"so existing deployments keep working.",
+		Flags:  append([]cli.Flag{logLevelFlag}, serveFlags...),
+		Before: setupLogging,
+		Action: serve,
+		Commands: []*cli.Command{
+			{
+				Name:   "serve",
+				Usage:  "start the web and gRPC servers",
+				Flags:  serveFlags,
+				Action: serve,
+			},
+			migrateCommand,
+			importCommand,
+			exportCommand,
+			partnerCommand,
+			matchCommand,
+		},
+	}
+}
+
+// logLevelFlag is the lowest level of the lines logged by every command. It is
+// given before the command, like aroundhome --log-level debug serve.
+var logLevelFlag = &cli.StringFlag{
+	Name:    "log-level",
+	Usage:   "debug, info, warn or error",
+	EnvVars: []string{"LOG_LEVEL"},
+	Value:   "info",
+}
+
+func setupLogging(c *cli.Context) error {
+	level, err := logging.ParseLevel(c.String("log-level"))
+	if err != nil {
+		return err
+	}
+	logging.SetDefault(logging.New(os.Stderr, level))
+	return nil
+}
+
+// outputFlag selects how partner and match print their result.
+var outputFlag = &cli.StringFlag{
+	Name:  "output",
+	Usage: "table or json",
+	Value: "table",
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS
+    public.partners (
+                        id serial NOT NULL,
+                        name character varying(255) NOT NULL,
+                        lat numeric NOT NULL,
+                        lng numeric NOT NULL,
+                        radius numeric NOT NULL DEFAULT 0,
+                        rating double precision NULL,
+                        flooring_experience text [] NULL,
+                        CONSTRAINT partners_pkey PRIMARY KEY (id)
+);
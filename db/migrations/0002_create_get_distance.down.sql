@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS getDistance(DECIMAL, DECIMAL, DECIMAL, DECIMAL);
@@ -0,0 +1,31 @@
+CREATE OR REPLACE FUNCTION
+    getDistance(
+    lat1 DECIMAL,
+    lng1 DECIMAL,
+    lat2 DECIMAL,
+    lng2 DECIMAL
+) RETURNS DECIMAL AS $$
+
+declare distance DECIMAL;
+
+begin
+select
+    (
+            6371 * acos(
+                        cos(radians(lat2)) * cos(radians(lat1)) * cos(radians(lng1) - radians(lng2)) + sin(radians(lat2)) * sin(radians(lat1))
+            )
+        ) INTO distance;
+return distance;
+end;
+
+$$ language plpgsql;
+
+DROP INDEX IF EXISTS public.partners_flooring_experience_idx;
+
+DROP INDEX IF EXISTS public.partners_coverage_idx;
+
+ALTER TABLE public.partners DROP COLUMN IF EXISTS coverage;
+
+DROP FUNCTION IF EXISTS coverage_box(double precision, double precision, double precision);
+
+DROP FUNCTION IF EXISTS distance_km(double precision, double precision, double precision, double precision);
@@ -0,0 +1,58 @@
+-- Great-circle distance in kilometers using the haversine formula, which stays
+-- accurate for nearby points where the spherical law of cosines rounds to NaN.
+CREATE FUNCTION
+    distance_km(
+    lat1 double precision,
+    lng1 double precision,
+    lat2 double precision,
+    lng2 double precision
+) RETURNS double precision AS $$
+select
+    2 * 6371 * asin(least(1, sqrt(
+                power(sin(radians(lat2 - lat1) / 2), 2) +
+                cos(radians(lat1)) * cos(radians(lat2)) * power(sin(radians(lng2 - lng1) / 2), 2)
+        )));
+$$ language sql immutable strict parallel safe;
+
+-- Box around the operating circle with longitude as x and latitude as y.
+-- Circles reaching a pole or crossing the antimeridian span all longitudes.
+CREATE FUNCTION
+    coverage_box(
+    lat double precision,
+    lng double precision,
+    radius double precision
+) RETURNS box AS $$
+select
+    case
+        when lat - degrees(radius / 6371) <= -90 or lat + degrees(radius / 6371) >= 90
+            or sin(radius / 6371) >= cos(radians(lat))
+            then box(point(-180, greatest(lat - degrees(radius / 6371), -90)),
+                     point(180, least(lat + degrees(radius / 6371), 90)))
+        when lng - degrees(asin(least(1, sin(radius / 6371) / cos(radians(lat))))) < -180
+            or lng + degrees(asin(least(1, sin(radius / 6371) / cos(radians(lat))))) > 180
+            then box(point(-180, lat - degrees(radius / 6371)),
+                     point(180, lat + degrees(radius / 6371)))
+        else box(point(lng - degrees(asin(sin(radius / 6371) / cos(radians(lat)))), lat - degrees(radius / 6371)),
+                 point(lng + degrees(asin(sin(radius / 6371) / cos(radians(lat)))), lat + degrees(radius / 6371)))
+        end;
+$$ language sql immutable strict parallel safe;
+
+ALTER TABLE
+    public.partners
+    ADD COLUMN
+        coverage box GENERATED ALWAYS AS (coverage_box(lat, lng, radius)) STORED;
+
+CREATE INDEX partners_coverage_idx ON public.partners USING gist (coverage);
+
+CREATE INDEX partners_flooring_experience_idx ON public.partners USING gin (flooring_experience);
+
+-- keep getDistance for ad-hoc queries, but without the NaN and plpgsql overhead
+CREATE OR REPLACE FUNCTION
+    getDistance(
+    lat1 DECIMAL,
+    lng1 DECIMAL,
+    lat2 DECIMAL,
+    lng2 DECIMAL
+) RETURNS DECIMAL AS $$
+select distance_km(lat1, lng1, lat2, lng2)::DECIMAL;
+$$ language sql immutable strict parallel safe;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS
+    public.materials (
+                         code text NOT NULL,
+                         parent text NULL,
+                         names jsonb NOT NULL DEFAULT '{}',
+                         active boolean NOT NULL DEFAULT true,
+                         CONSTRAINT materials_pkey PRIMARY KEY (code),
+                         CONSTRAINT materials_parent_fkey FOREIGN KEY (parent) REFERENCES public.materials (code)
+);
+
+INSERT INTO public.materials (code, parent, names) VALUES
+    ('hard_floors', NULL, '{"en": "Hard floors", "de": "Hartböden"}'),
+    ('soft_floors', NULL, '{"en": "Soft floors", "de": "Weichböden"}'),
+    ('carpet', 'soft_floors', '{"en": "Carpet", "de": "Teppich"}'),
+    ('cement', 'hard_floors', '{"en": "Cement", "de": "Zement"}'),
+    ('tiles', 'hard_floors', '{"en": "Tiles", "de": "Fliesen"}'),
+    ('wood', 'hard_floors', '{"en": "Wood", "de": "Holz"}')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    public.partners
+    DROP COLUMN IF EXISTS min_sqm,
+    DROP COLUMN IF EXISTS max_sqm;
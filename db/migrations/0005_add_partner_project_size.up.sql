@@ -0,0 +1,5 @@
+-- project sizes in square meters a partner prefers, NULL for no limit
+ALTER TABLE
+    public.partners
+    ADD COLUMN IF NOT EXISTS min_sqm numeric NULL,
+    ADD COLUMN IF NOT EXISTS max_sqm numeric NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.customer_request_matches;
+
+DROP TABLE IF EXISTS public.customer_requests;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS
+    public.customer_requests (
+                                 id bigserial NOT NULL,
+                                 materials text [] NOT NULL,
+                                 lat double precision NOT NULL,
+                                 lng double precision NOT NULL,
+                                 sqm numeric NULL,
+                                 phone text NOT NULL,
+                                 created_at timestamptz NOT NULL DEFAULT now(),
+                                 updated_at timestamptz NOT NULL DEFAULT now(),
+                                 CONSTRAINT customer_requests_pkey PRIMARY KEY (id)
+);
+
+-- the ranked partners shown for a request, with a snapshot of the partner data at that time
+CREATE TABLE IF NOT EXISTS
+    public.customer_request_matches (
+                                        request_id bigint NOT NULL,
+                                        rank integer NOT NULL,
+                                        partner_id integer NOT NULL,
+                                        distance double precision NOT NULL,
+                                        score double precision NOT NULL,
+                                        factors jsonb NOT NULL DEFAULT '{}',
+                                        partner jsonb NOT NULL,
+                                        CONSTRAINT customer_request_matches_pkey PRIMARY KEY (request_id, rank),
+                                        CONSTRAINT customer_request_matches_request_id_fkey FOREIGN KEY (request_id)
+                                            REFERENCES public.customer_requests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS customer_request_matches_partner_id_idx ON public.customer_request_matches (partner_id);
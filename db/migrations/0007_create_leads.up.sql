@@ -0,0 +1,22 @@
+-- a customer request offered to one of its matched partners
+CREATE TABLE IF NOT EXISTS
+    public.leads (
+                     id bigserial NOT NULL,
+                     request_id bigint NOT NULL,
+                     partner_id integer NOT NULL,
+                     rank integer NOT NULL,
+                     state text NOT NULL DEFAULT 'offered',
+                     offered_at timestamptz NOT NULL DEFAULT now(),
+                     expires_at timestamptz NOT NULL,
+                     updated_at timestamptz NOT NULL DEFAULT now(),
+                     CONSTRAINT leads_pkey PRIMARY KEY (id),
+                     CONSTRAINT leads_request_id_partner_id_key UNIQUE (request_id, partner_id),
+                     CONSTRAINT leads_state_check CHECK (state IN ('offered', 'viewed', 'accepted', 'declined', 'expired')),
+                     CONSTRAINT leads_request_id_fkey FOREIGN KEY (request_id)
+                         REFERENCES public.customer_requests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS leads_partner_id_idx ON public.leads (partner_id);
+
+-- open leads are swept for expiry
+CREATE INDEX IF NOT EXISTS leads_expires_at_idx ON public.leads (expires_at) WHERE state IN ('offered', 'viewed');
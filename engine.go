@@ -0,0 +1,34 @@
+package main
+
+import (
+	"aroundHome/app/repository"
+	"aroundHome/app/spatial"
+	"context"
+	"fmt"
+	"github.com/urfave/cli/v2"
+)
+
+// matchEngineFlag selects how serve and match find the partners of a request.
+var matchEngineFlag = &cli.StringFlag{
+	Name:    "match-engine",
+	Usage:   "sql to match in PostgreSQL, memory to match against an in-memory index",
+	EnvVars: []string{"MATCH_ENGINE"},
+	Value:   "sql",
+}
+
+// matchEngine returns the repository matching partners with engine: partners
+// itself for sql, or for memory an index loaded from partners, which is also
+// returned as indexed to be refreshed by the caller.
+func matchEngine(ctx context.Context, engine string, partners repository.PartnerRepository) (matched repository.PartnerRepository, indexed *spatial.Indexed, err error) {
+	switch engine {
+	case "", "sql":
+		return partners, nil, nil
+	case "memory":
+		indexed, err := spatial.NewIndexed(ctx, partners, spatial.DefaultCellSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return indexed, indexed, nil
+	}
+	return nil, nil, fmt.Errorf("unknown match engine %q, use sql or memory", engine)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/exporter"
+	"aroundHome/app/repository"
+	"bufio"
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"io"
+	"os"
+)
+
+var exportCommand = &cli.Command{
+	Name:  "export",
+	Usage: "write all partners to a file",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "csv, ndjson or geojson, derived from the -o extension or csv if empty",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "output file, - for standard output",
+			Value:   "-",
+		},
+	},
+	Action: exportPartners,
+}
+
+// exportPartners runs the export command.
+func exportPartners(c *cli.Context) error {
+	if c.NArg() != 0 {
+		return fmt.Errorf("export takes no arguments, use -o for the file")
+	}
+	output := c.String("output")
+
+	format := exporter.CSV
+	var err error
+	if name := c.String("format"); name != "" {
+		format, err = exporter.ParseFormat(name)
+	} else if output != "-" {
+		format, err = exporter.FormatFromPath(output)
+	}
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "-" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	w := bufio.NewWriter(out)
+
+	db, err := app.DatabaseConnect(c.Context)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := exporter.Write(c.Context, w, repository.NewPostgres(db), format); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/importer"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"io"
+	"os"
+)
+
+var importCommand = &cli.Command{
+	Name:      "import",
+	Usage:     "create or update partners from a file",
+	ArgsUsage: "file|-",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "csv, json or ndjson, derived from the file extension if empty",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only validate, do not write anything",
+		},
+		&cli.IntFlag{
+			Name:  "batch-size",
+			Usage: "partners upserted per statement, all in one transaction",
+			Value: importer.DefaultBatchSize,
+		},
+	},
+	Action: importPartners,
+}
+
+// importPartners runs the import command, it exits with 1 if rows failed.
+func importPartners(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("import needs exactly one file, - for standard input")
+	}
+	path := c.Args().First()
+
+	var format importer.Format
+	var err error
+	if name := c.String("format"); name != "" {
+		format, err = importer.ParseFormat(name)
+	} else {
+		format, err = importer.FormatFromPath(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	rows, err := importer.Read(input, format)
+	if err != nil {
+		return err
+	}
+
+	db, err := app.DatabaseConnect(c.Context)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	// partners are validated against the material catalog of the database, even in a dry run
+	catalog, err := taxonomy.NewStore(repository.NewPostgresMaterials(db), taxonomy.DefaultTTL).Catalog(c.Context)
+	if err != nil {
+		return err
+	}
+	report, err := importer.Import(c.Context, repository.NewPostgres(db), catalog, rows, importer.Options{DryRun: c.Bool("dry-run"), BatchSize: c.Int("batch-size")})
+	printImportReport(report)
+	if err != nil {
+		return err
+	}
+	if len(report.Failed) > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+func printImportReport(report *importer.Report) {
+	for _, f := range report.Failed {
+		if f.Id != 0 {
+			fmt.Printf("row %d (id %d): %v\n", f.Row, f.Id, f.Errors)
+		} else {
+			fmt.Printf("row %d: %v\n", f.Row, f.Errors)
+		}
+	}
+	if report.DryRun {
+		fmt.Printf("dry run: %d partners valid, %d rows failed\n", report.Valid, len(report.Failed))
+	} else {
+		fmt.Printf("imported %d of %d valid partners, %d rows failed\n", report.Imported, report.Valid, len(report.Failed))
+	}
+}
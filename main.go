@@ -1,13 +1,9 @@
 package main
 
 import (
-	"aroundHome/app"
-	"database/sql"
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/recover"
+	"aroundHome/app/logging"
+	"context"
 	_ "github.com/lib/pq"
-	"log"
 	"os"
 )
 
@@ -27,30 +23,8 @@ import (
 // @BasePath /
 // @schemes http
 func main() {
-
-	// Fiber instance
-	webApp := fiber.New()
-
-	// Middleware
-	webApp.Use(recover.New())
-	webApp.Use(cors.New())
-
-	db := app.DatabaseConnect()
-	defer func(db *sql.DB) {
-		err := db.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}(db)
-
-	app.Routes(webApp, db)
-
-	// Start Server
-	webPort := os.Getenv("PORT")
-	if webPort == "" {
-		webPort = "3000"
-	}
-	if err := webApp.Listen(":" + webPort); err != nil {
-		log.Fatal(err)
+	if err := newCLI().Run(os.Args); err != nil {
+		logging.Error(context.Background(), "exiting", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/commands"
+	"aroundHome/app/matching"
+	"aroundHome/app/ranking"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"github.com/urfave/cli/v2"
+	"os"
+)
+
+var matchCommand = &cli.Command{
+	Name:  "match",
+	Usage: "print the partners matched for a customer request, like GET /v1/query",
+	Flags: []cli.Flag{
+		&cli.Float64Flag{
+			Name:     "lat",
+			Usage:    "latitude of the customer",
+			Required: true,
+		},
+		&cli.Float64Flag{
+			Name:     "lng",
+			Usage:    "longitude of the customer",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     "material",
+			Usage:    "material codes of the catalog, repeated or comma separated",
+			Required: true,
+		},
+		&cli.Float64Flag{
+			Name:  "sqm",
+			Usage: "size of the floor in square meters, 0 if unknown",
+		},
+		matchEngineFlag,
+		outputFlag,
+	},
+	Action: match,
+}
+
+// match runs the match command with the match engine, matching and ranking of
+// the servers.
+func match(c *cli.Context) error {
+	out, err := commands.ParseOutput(c.String(outputFlag.Name))
+	if err != nil {
+		return err
+	}
+	ranker, err := ranking.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	db, err := app.DatabaseConnect(c.Context)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	postgres := repository.NewPostgres(db)
+	defer postgres.Close()
+	partners, _, err := matchEngine(c.Context, c.String(matchEngineFlag.Name), postgres)
+	if err != nil {
+		return err
+	}
+	matcher := &matching.Service{
+		Partners:  partners,
+		Materials: taxonomy.NewStore(repository.NewPostgresMaterials(db), taxonomy.DefaultTTL),
+		Ranker:    ranker,
+	}
+	request := matching.Request{
+		Lat:       c.Float64("lat"),
+		Lng:       c.Float64("lng"),
+		Materials: commands.Materials(c.StringSlice("material")),
+		Sqm:       c.Float64("sqm"),
+	}
+	return commands.Match(c.Context, os.Stdout, out, matcher, request)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"aroundHome/app"
+	"aroundHome/db/migrations"
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"strconv"
+	"time"
+)
+
+var migrateCommand = &cli.Command{
+	Name:  "migrate",
+	Usage: "change or show the version of the database schema",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "up",
+			Usage:  "apply all pending migrations",
+			Action: migrateUp,
+		},
+		{
+			Name:      "down",
+			Usage:     "revert the last migrations",
+			ArgsUsage: "[steps]",
+			Action:    migrateDown,
+		},
+		{
+			Name:   "status",
+			Usage:  "list the migrations and when they were applied",
+			Action: migrateStatus,
+		},
+	},
+}
+
+// withMigrator runs run with a migrator of the database.
+func withMigrator(c *cli.Context, run func(*migrations.Migrator) error) error {
+	db, err := app.DatabaseConnect(c.Context)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	return run(migrator)
+}
+
+func migrateUp(c *cli.Context) error {
+	return withMigrator(c, func(migrator *migrations.Migrator) error {
+		done, err := migrator.Up(c.Context)
+		for _, m := range done {
+			fmt.Printf("applied %d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return nil
+	})
+}
+
+func migrateDown(c *cli.Context) error {
+	steps := 1
+	if c.Args().Present() {
+		var err error
+		steps, err = strconv.Atoi(c.Args().First())
+		if err != nil || steps < 1 {
+			return fmt.Errorf("steps must be a positive integer")
+		}
+	}
+	return withMigrator(c, func(migrator *migrations.Migrator) error {
+		done, err := migrator.Down(c.Context, steps)
+		for _, m := range done {
+			fmt.Printf("reverted %d_%s\n", m.Version, m.Name)
+		}
+		return err
+	})
+}
+
+func migrateStatus(c *cli.Context) error {
+	return withMigrator(c, func(migrator *migrations.Migrator) error {
+		statuses, err := migrator.Status(c.Context)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.Applied {
+				applied = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/commands"
+	"aroundHome/app/repository"
+	"github.com/urfave/cli/v2"
+	"os"
+)
+
+var partnerCommand = &cli.Command{
+	Name:  "partner",
+	Usage: "look up partners",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "get",
+			Usage:     "print a partner",
+			ArgsUsage: "<id>",
+			Flags:     []cli.Flag{outputFlag},
+			Action:    getPartner,
+		},
+	},
+}
+
+// getPartner runs the partner get command.
+func getPartner(c *cli.Context) error {
+	id, err := commands.PartnerId(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+	out, err := commands.ParseOutput(c.String(outputFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	db, err := app.DatabaseConnect(c.Context)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return commands.GetPartner(c.Context, os.Stdout, out, repository.NewPostgres(db), id)
+}
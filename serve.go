@@ -0,0 +1,192 @@
+package main
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/drain"
+	"aroundHome/app/health"
+	"aroundHome/app/leads"
+	"aroundHome/app/logging"
+	"aroundHome/app/matching"
+	"aroundHome/app/metrics"
+	"aroundHome/app/problem"
+	"aroundHome/app/ranking"
+	"aroundHome/app/repository"
+	"aroundHome/app/rpc"
+	"aroundHome/app/taxonomy"
+	"aroundHome/app/tracing"
+	"aroundHome/db/migrations"
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/urfave/cli/v2"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var serveFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "port",
+		Usage:   "port of the web server",
+		EnvVars: []string{"PORT"},
+		Value:   "3000",
+	},
+	&cli.StringFlag{
+		Name:    "grpc-port",
+		Usage:   "port of the gRPC server",
+		EnvVars: []string{"GRPC_PORT"},
+		Value:   "9090",
+	},
+	matchEngineFlag,
+	&cli.DurationFlag{
+		Name:    "match-index-refresh",
+		Usage:   "interval the in-memory index is reloaded in",
+		EnvVars: []string{"MATCH_INDEX_REFRESH"},
+		Value:   time.Minute,
+	},
+	&cli.StringFlag{
+		Name:    "trace-exporter",
+		Usage:   "none, stdout or otlp to send traces to the collector of OTEL_EXPORTER_OTLP_ENDPOINT",
+		EnvVars: []string{"TRACE_EXPORTER"},
+		Value:   tracing.None,
+	},
+	&cli.DurationFlag{
+		Name:    "shutdown-timeout",
+		Usage:   "time the requests in flight have to finish after SIGTERM or SIGINT",
+		EnvVars: []string{"SHUTDOWN_TIMEOUT"},
+		Value:   10 * time.Second,
+	},
+}
+
+// serve runs the serve command, the web and gRPC servers share the services.
+// SIGTERM or SIGINT shuts both down gracefully, see drain.Tracker.Shutdown.
+func serve(c *cli.Context) error {
+	if c.Args().Present() {
+		return fmt.Errorf("unknown command %q", c.Args().First())
+	}
+	// ctx ends with the first signal, stopping the startup and the background work
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(c.Context, c.String("trace-exporter"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logging.Error(context.Background(), "shutting down tracing failed", "error", err)
+		}
+	}()
+
+	// Fiber instance
+	webApp := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+
+	// Middleware, the tracker of the requests in flight first as it sets their
+	// context, the request id next so every line logged for a request has
+	// it, the access log, tracing and metrics before the errors are answered to
+	// see the final status, recover after that to answer its panics
+	tracker := drain.New()
+	webApp.Use(tracker.Middleware())
+	webApp.Use(logging.Middleware())
+	webApp.Use(tracing.Middleware())
+	webApp.Use(metrics.Middleware())
+	webApp.Use(problem.Middleware())
+	webApp.Use(recover.New())
+	webApp.Use(cors.New())
+
+	db, err := app.DatabaseConnect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	metrics.RegisterDB(db, "aroundhome")
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Check(ctx); err != nil {
+		return err
+	}
+
+	checks := []health.Check{health.Database(db), health.Schema(migrator)}
+	postgres := repository.NewPostgres(db)
+	// closed before the database, deferred later
+	defer postgres.Close()
+
+	// the background work ends before the database is closed, also when the
+	// startup fails
+	ctx, cancel := context.WithCancel(ctx)
+	var background sync.WaitGroup
+	defer func() {
+		cancel()
+		background.Wait()
+	}()
+	partners, indexed, err := matchEngine(ctx, c.String(matchEngineFlag.Name), postgres)
+	if err != nil {
+		return err
+	}
+	if indexed != nil {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			indexed.Run(ctx, c.Duration("match-index-refresh"))
+		}()
+		// a few failed refreshes in a row make the instance unready
+		checks = append(checks, health.Index(indexed, 3*c.Duration("match-index-refresh")))
+	}
+
+	ranker, err := ranking.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	requests := repository.NewPostgresRequests(db)
+	leadRepository := repository.NewPostgresLeads(db)
+	dispatcher, err := leads.FromEnv(leadRepository, requests)
+	if err != nil {
+		return err
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		dispatcher.Run(ctx, time.Minute)
+	}()
+
+	materials := taxonomy.NewStore(repository.NewPostgresMaterials(db), taxonomy.DefaultTTL)
+	services := app.Services{
+		Partners:   partners,
+		Materials:  materials,
+		Matcher:    &matching.Service{Partners: partners, Materials: materials, Ranker: ranker},
+		Requests:   requests,
+		Leads:      leadRepository,
+		Dispatcher: dispatcher,
+		Checks:     checks,
+	}
+	app.Routes(webApp, services)
+
+	// gRPC server for internal services, sharing the services of the routes
+	grpcListener, err := net.Listen("tcp", ":"+c.String("grpc-port"))
+	if err != nil {
+		return err
+	}
+	grpcServer := rpc.NewServer(services, tracker.ServerOptions()...)
+
+	// Start Server
+	webListener, err := net.Listen("tcp", ":"+c.String("port"))
+	if err != nil {
+		grpcListener.Close()
+		return err
+	}
+	// a second signal ends the process right away
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return tracker.Run(ctx, webApp, webListener, grpcServer, grpcListener, c.Duration("shutdown-timeout"))
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"aroundHome/app"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBacksOff(t *testing.T) {
+	var attempts []time.Time
+	err := app.Retry(context.Background(), 5*time.Second, func(context.Context) error {
+		attempts = append(attempts, time.Now())
+		if len(attempts) < 4 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, attempts, 4)
+	// the pauses double from 100ms
+	for i, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		assert.GreaterOrEqual(t, attempts[i+1].Sub(attempts[i]), want)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	refused := errors.New("connection refused")
+	start := time.Now()
+	err := app.Retry(context.Background(), 250*time.Millisecond, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("try must be bounded by the maximum wait")
+		}
+		return refused
+	})
+	assert.ErrorIs(t, err, refused)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// a try cut short by the deadline reports the failure before it
+	attempts := 0
+	err = app.Retry(context.Background(), 150*time.Millisecond, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return refused
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, refused)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = app.Retry(ctx, time.Minute, func(ctx context.Context) error { return ctx.Err() })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDatabaseConnectWaits(t *testing.T) {
+	t.Setenv("PG_HOSTNAME", "127.0.0.1")
+	t.Setenv("PG_PORT", "1")
+	t.Setenv("PG_CONNECT_WAIT", "300ms")
+	start := time.Now()
+	db, err := app.DatabaseConnect(context.Background())
+	assert.Nil(t, db)
+	assert.ErrorContains(t, err, "connecting to the database")
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	t.Setenv("PG_CONNECT_WAIT", "soon")
+	_, err = app.DatabaseConnect(context.Background())
+	assert.ErrorContains(t, err, "PG_CONNECT_WAIT")
+}
+
+func TestDataSourceName(t *testing.T) {
+	t.Setenv("PG_HOSTNAME", "db")
+	t.Setenv("PG_PORT", "54320")
+	dsn, err := app.DataSourceName()
+	require.NoError(t, err)
+	assert.Contains(t, dsn, "host=db port=54320 ")
+
+	t.Setenv("PG_PORT", "postgres")
+	_, err = app.DataSourceName()
+	assert.ErrorContains(t, err, "PG_PORT")
+	_, err = app.DatabaseConnect(context.Background())
+	assert.ErrorContains(t, err, "PG_PORT")
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"aroundHome/app/client"
+	"aroundHome/app/dto"
+	"aroundHome/app/validation"
+	"aroundHome/tests/fixtures"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHandler serves the routes of main with test partners over net/http.
+func testHandler() http.Handler {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	// fiber serves fasthttp, Test runs a net/http request through the app
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RequestURI = ""
+		resp, err := webApp.Test(r, -1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for key, values := range resp.Header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	})
+}
+
+func testClient(t *testing.T, opts ...client.Option) *client.Client {
+	server := httptest.NewServer(testHandler())
+	t.Cleanup(server.Close)
+	return client.New(server.URL, opts...)
+}
+
+func TestGetPartner(t *testing.T) {
+	c := testClient(t)
+
+	partner, err := c.GetPartner(context.Background(), 883)
+	require.NoError(t, err)
+	assert.Equal(t, "Meevee", partner.Name)
+	assert.Equal(t, []string{"carpet", "tiles", "wood"}, partner.Materials)
+
+	_, err = c.GetPartner(context.Background(), 2)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	var apiErr *client.Error
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 404, apiErr.Status)
+		assert.Equal(t, "partner not found", apiErr.Detail)
+	}
+}
+
+func TestListPartners(t *testing.T) {
+	c := testClient(t)
+
+	minRating := 5.0
+	page, err := c.ListPartners(context.Background(), client.ListOptions{Materials: []string{"wood"}, MinRating: &minRating, Sort: "-rating", Limit: 1})
+	require.NoError(t, err)
+	if assert.Len(t, page.Partners, 1) {
+		assert.Equal(t, int16(805), page.Partners[0].Id)
+	}
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = c.ListPartners(context.Background(), client.ListOptions{Materials: []string{"wood"}, MinRating: &minRating, Sort: "-rating", Limit: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	if assert.Len(t, page.Partners, 1) {
+		assert.Equal(t, int16(883), page.Partners[0].Id)
+	}
+	assert.Empty(t, page.NextCursor)
+
+	page, err = c.ListPartners(context.Background(), client.ListOptions{Ids: []int16{1, 805}})
+	require.NoError(t, err)
+	assert.Len(t, page.Partners, 2)
+
+	_, err = c.ListPartners(context.Background(), client.ListOptions{Sort: "size"})
+	assert.ErrorIs(t, err, client.ErrInvalid)
+}
+
+func TestMatch(t *testing.T) {
+	c := testClient(t)
+
+	result, err := c.Match(context.Background(), client.MatchRequest{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet", "tiles"}, Sqm: 35, Phone: "0160153700132"})
+	require.NoError(t, err)
+	assert.Equal(t, "0160153700132", result.Phone)
+	assert.Equal(t, float64(35), result.Sqm)
+	ids := make([]int16, 0)
+	for _, m := range result.Matches {
+		ids = append(ids, m.Id)
+	}
+	assert.Equal(t, []int16{883, 1}, ids)
+	assert.Equal(t, dto.Kilometers, result.Matches[0].Distance.Unit)
+
+	_, err = c.Match(context.Background(), client.MatchRequest{Lat: 40.076762, Lng: 113.300129, Materials: []string{"vinyl"}})
+	assert.ErrorIs(t, err, client.ErrInvalid)
+}
+
+func TestPartnerCrud(t *testing.T) {
+	c := testClient(t)
+	ctx := context.Background()
+
+	created, err := c.CreatePartner(ctx, dto.Partner{Name: "Kwideo", Lat: 52.52, Lng: 13.405, RadiusKm: 50, Rating: 7.5, Materials: []string{"wood"}})
+	require.NoError(t, err)
+	assert.Equal(t, int16(884), created.Id)
+
+	_, err = c.CreatePartner(ctx, dto.Partner{Id: 884, Name: "Kwideo", Lat: 52.52, Lng: 13.405, RadiusKm: 50, Rating: 7.5, Materials: []string{"wood"}})
+	assert.ErrorIs(t, err, client.ErrConflict)
+
+	_, err = c.CreatePartner(ctx, dto.Partner{Name: "Kwideo", Lat: 52.52, Lng: 13.405, RadiusKm: 50, Rating: 11, Materials: []string{"wood"}})
+	var apiErr *client.Error
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 422, apiErr.Status)
+		assert.Equal(t, validation.Errors{{Field: "rating", Message: "must be between 0 and 10"}}, apiErr.Errors)
+	}
+
+	created.Name = "Kwideo Berlin"
+	updated, err := c.UpdatePartner(ctx, *created)
+	require.NoError(t, err)
+	assert.Equal(t, "Kwideo Berlin", updated.Name)
+
+	patched, err := c.PatchPartner(ctx, created.Id, map[string]interface{}{"rating": 8})
+	require.NoError(t, err)
+	assert.Equal(t, float64(8), patched.Rating)
+	assert.Equal(t, "Kwideo Berlin", patched.Name)
+
+	require.NoError(t, c.DeletePartner(ctx, created.Id))
+	assert.ErrorIs(t, c.DeletePartner(ctx, created.Id), client.ErrNotFound)
+}
+
+// flaky answers the first failures requests with 503 before passing them to next.
+func flaky(failures int32, next http.Handler) (http.Handler, *int32) {
+	calls := new(int32)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}), calls
+}
+
+func TestRetries(t *testing.T) {
+	handler, calls := flaky(2, testHandler())
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	c := client.New(server.URL, client.WithRetries(2, time.Millisecond))
+	partner, err := c.GetPartner(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Lazz", partner.Name)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	// creating is not idempotent and never repeated
+	atomic.StoreInt32(calls, 0)
+	_, err = c.CreatePartner(context.Background(), dto.Partner{Name: "Kwideo", Lat: 52.52, Lng: 13.405, RadiusKm: 50, Materials: []string{"wood"}})
+	var apiErr *client.Error
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 503, apiErr.Status)
+		assert.Equal(t, "Service Unavailable", apiErr.Title)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	// the retries are given up with the last error
+	handler, calls = flaky(10, testHandler())
+	server.Config.Handler = handler
+	_, err = c.GetPartner(context.Background(), 1)
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestTimeoutAndCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := client.New(server.URL, client.WithTimeout(10*time.Millisecond), client.WithRetries(1, time.Millisecond))
+	_, err := c.GetPartner(context.Background(), 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.New(server.URL).GetPartner(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
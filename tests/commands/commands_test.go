@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"aroundHome/app/commands"
+	"aroundHome/app/matching"
+	"aroundHome/app/validation"
+	"aroundHome/tests/fixtures"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutput(t *testing.T) {
+	out, err := commands.ParseOutput("json")
+	require.NoError(t, err)
+	assert.Equal(t, commands.JSON, out)
+	_, err = commands.ParseOutput("yaml")
+	assert.EqualError(t, err, `unknown output "yaml", use table or json`)
+}
+
+func TestPartnerId(t *testing.T) {
+	id, err := commands.PartnerId([]string{"883"})
+	require.NoError(t, err)
+	assert.Equal(t, int16(883), id)
+
+	for _, args := range [][]string{nil, {"1", "2"}, {"abc"}, {"40000"}} {
+		_, err := commands.PartnerId(args)
+		assert.Errorf(t, err, "%q", args)
+	}
+}
+
+func TestGetPartner(t *testing.T) {
+	partners := fixtures.Services(fixtures.Partners()...).Partners
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	require.NoError(t, commands.GetPartner(ctx, &buf, commands.Table, partners, 883))
+	assert.Equal(t, `ID         883
+NAME       Meevee
+LAT        39.296173
+LNG        113.6907
+RADIUS_KM  127.98
+RATING     5.25
+MATERIALS  carpet,tiles,wood
+MIN_SQM    0
+MAX_SQM    0
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, commands.GetPartner(ctx, &buf, commands.JSON, partners, 883))
+	var partner map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &partner))
+	assert.Equal(t, "Meevee", partner["name"])
+	assert.Equal(t, 127.98, partner["radius_km"])
+	assert.Equal(t, []interface{}{"carpet", "tiles", "wood"}, partner["materials"])
+
+	buf.Reset()
+	assert.EqualError(t, commands.GetPartner(ctx, &buf, commands.Table, partners, 2), "partner 2 not found")
+	assert.Empty(t, buf.String())
+}
+
+func TestMatch(t *testing.T) {
+	matcher := fixtures.Services(fixtures.Partners()...).Matcher
+	ctx := context.Background()
+	request := matching.Request{Lat: 40.076762, Lng: 113.300129, Materials: commands.Materials([]string{"carpet", "tiles"}), Sqm: 35}
+
+	var buf bytes.Buffer
+	require.NoError(t, commands.Match(ctx, &buf, commands.Table, matcher, request))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"ID", "NAME", "DISTANCE_KM", "SCORE", "RATING", "MATERIALS"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"883", "Meevee"}, strings.Fields(lines[1])[:2])
+	assert.Equal(t, []string{"1", "Lazz", "0.00"}, strings.Fields(lines[2])[:3])
+
+	buf.Reset()
+	require.NoError(t, commands.Match(ctx, &buf, commands.JSON, matcher, request))
+	var result struct {
+		Sqm     float64
+		Matches []struct{ Id int16 }
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, float64(35), result.Sqm)
+	assert.Len(t, result.Matches, 2)
+
+	// unknown materials are rejected like by the API
+	request.Materials = commands.Materials([]string{"carpet,vinyl"})
+	err := commands.Match(ctx, &buf, commands.Table, matcher, request)
+	var errs validation.Errors
+	if assert.ErrorAs(t, err, &errs) {
+		assert.Equal(t, "materials", errs[0].Field)
+	}
+}
+
+func TestMaterials(t *testing.T) {
+	assert.Equal(t, []string{"carpet", "tiles", "wood"}, commands.Materials([]string{"carpet,tiles", "wood"}))
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"aroundHome/app/problem"
+	"aroundHome/app/repository"
+	"aroundHome/app/validation"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestProblemResponses(t *testing.T) {
+	tests := []struct {
+		description    string
+		method         string
+		route          string
+		body           string
+		expectedCode   int
+		expectedDetail string
+	}{
+		{
+			description:    "unknown partner",
+			method:         "GET",
+			route:          "/partners/2",
+			expectedCode:   404,
+			expectedDetail: "partner not found",
+		},
+		{
+			description:    "partner id out of range",
+			method:         "GET",
+			route:          "/partners/100000",
+			expectedCode:   400,
+			expectedDetail: "id must be an integer",
+		},
+		{
+			description:    "address without a comma",
+			method:         "GET",
+			route:          "/query/?address=40.076762&material=carpet",
+			expectedCode:   400,
+			expectedDetail: "address must be in format latitude,longitude",
+		},
+		{
+			description:    "address that is no number",
+			method:         "GET",
+			route:          "/query/?address=north,east&material=carpet",
+			expectedCode:   400,
+			expectedDetail: "latitude of the address must be a number",
+		},
+		{
+			description:    "sqm that is no number",
+			method:         "GET",
+			route:          "/query/?address=40.076762,113.300129&material=carpet&sqm=large",
+			expectedCode:   400,
+			expectedDetail: "sqm: must be a number",
+		},
+		{
+			description:    "unknown material",
+			method:         "GET",
+			route:          "/query/?address=40.076762,113.300129&material=vinyl",
+			expectedCode:   400,
+			expectedDetail: "materials: unknown material \"vinyl\", allowed are carpet, cement, tiles, wood",
+		},
+		{
+			description:  "body that is no JSON",
+			method:       "POST",
+			route:        "/partners",
+			body:         `{"Name": `,
+			expectedCode: 400,
+		},
+		{
+			description:    "invalid partner",
+			method:         "POST",
+			route:          "/partners",
+			body:           `{"Name": "Kwideo", "Lat": 52.52, "Lng": 13.405, "Radius": 50, "Rating": 11, "FlooringExperience": "{wood}"}`,
+			expectedCode:   422,
+			expectedDetail: "rating: must be between 0 and 10",
+		},
+		{
+			description:    "unknown lead",
+			method:         "POST",
+			route:          "/leads/1/accept",
+			expectedCode:   404,
+			expectedDetail: "lead not found",
+		},
+		{
+			description:  "unknown route",
+			method:       "GET",
+			route:        "/unknown",
+			expectedCode: 404,
+		},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.route, strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := webApp.Test(req, -1)
+		if !assert.NoErrorf(t, err, test.description) {
+			continue
+		}
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		assert.Equalf(t, problem.ContentType, resp.Header.Get("Content-Type"), test.description)
+
+		body := new(problem.Problem)
+		assert.NoErrorf(t, json.NewDecoder(resp.Body).Decode(body), test.description)
+		assert.Equalf(t, test.expectedCode, body.Status, test.description)
+		assert.Equalf(t, "about:blank", body.Type, test.description)
+		assert.NotEmptyf(t, body.Title, test.description)
+		assert.Equalf(t, test.route, body.Instance, test.description)
+		if test.expectedDetail != "" {
+			assert.Equalf(t, test.expectedDetail, body.Detail, test.description)
+		}
+	}
+}
+
+func TestProblemHidesInternalErrors(t *testing.T) {
+	p := problem.From(errors.New("pq: password authentication failed"))
+	assert.Equal(t, 500, p.Status)
+	assert.Equal(t, "Internal Server Error", p.Title)
+	assert.Empty(t, p.Detail)
+}
+
+func TestProblemCodes(t *testing.T) {
+	tests := []struct {
+		err     error
+		graphql string
+		grpc    codes.Code
+	}{
+		{err: problem.BadRequest("id must be an integer"), graphql: "BAD_USER_INPUT", grpc: codes.InvalidArgument},
+		{err: validation.Errors{{Field: "rating", Message: "must be between 0 and 10"}}, graphql: "BAD_USER_INPUT", grpc: codes.InvalidArgument},
+		{err: repository.ErrNotFound, graphql: "NOT_FOUND", grpc: codes.NotFound},
+		{err: repository.ErrMaterialInUse, graphql: "CONFLICT", grpc: codes.FailedPrecondition},
+		{err: errors.New("connection refused"), graphql: "INTERNAL", grpc: codes.Internal},
+	}
+	for _, test := range tests {
+		code, ok := problem.From(test.err).Code()
+		assert.Equalf(t, test.graphql != "INTERNAL", ok, "%v", test.err)
+		assert.Equalf(t, problem.Code{GraphQL: test.graphql, GRPC: test.grpc}, code, "%v", test.err)
+	}
+}
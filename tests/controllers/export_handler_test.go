@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"aroundHome/app/importer"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHandler(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	// CSV and NDJSON exports can be imported again
+	for _, format := range []importer.Format{importer.CSV, importer.NDJSON} {
+		req := httptest.NewRequest("GET", "/partners/export?format="+string(format), nil)
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, 200, resp.StatusCode, "export %s", format)
+
+		rows, err := importer.Read(resp.Body, format)
+		assert.NoErrorf(t, err, "export %s", format)
+		if assert.Lenf(t, rows, 3, "export %s", format) {
+			assert.Equalf(t, fixtures.Partners()[0], rows[0].Partner, "export %s", format)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/partners/export?format=geojson", nil)
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/geo+json", resp.Header.Get("Content-Type"))
+
+	var collection struct {
+		Type     string
+		Features []struct {
+			Id       int16
+			Geometry struct {
+				Type        string
+				Coordinates []float32
+			}
+			Properties struct {
+				Radius             float32
+				FlooringExperience []string `json:"flooring_experience"`
+			}
+		}
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	if assert.Len(t, collection.Features, 3) {
+		first := collection.Features[0]
+		assert.Equal(t, int16(1), first.Id)
+		assert.Equal(t, "Point", first.Geometry.Type)
+		assert.Equal(t, []float32{113.300129, 40.076762}, first.Geometry.Coordinates)
+		assert.Equal(t, float32(108.83), first.Properties.Radius)
+		assert.Equal(t, []string{"carpet", "tiles"}, first.Properties.FlooringExperience)
+	}
+
+	req = httptest.NewRequest("GET", "/partners/export?format=xml", nil)
+	resp, _ = webApp.Test(req, -1)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestExportV1Handler(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	// v1 exports use the fields of the v1 partners and can be imported there again
+	for _, format := range []importer.Format{importer.CSV, importer.NDJSON} {
+		req := httptest.NewRequest("GET", "/v1/partners/export?format="+string(format), nil)
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, 200, resp.StatusCode, "export %s", format)
+
+		rows, err := importer.ReadV1(resp.Body, format)
+		assert.NoErrorf(t, err, "export %s", format)
+		if assert.Lenf(t, rows, 3, "export %s", format) {
+			assert.Equalf(t, fixtures.Partners()[0], rows[0].Partner, "export %s", format)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/v1/partners/export?format=ndjson", nil)
+	resp, _ := webApp.Test(req, -1)
+	var first map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&first))
+	assert.Equal(t, 108.83, first["radius_km"])
+	assert.Equal(t, []interface{}{"carpet", "tiles"}, first["materials"])
+	assert.NotContains(t, first, "radius")
+	assert.NotContains(t, first, "flooring_experience")
+
+	req = httptest.NewRequest("GET", "/v1/partners/export?format=geojson", nil)
+	resp, _ = webApp.Test(req, -1)
+	var collection struct {
+		Features []struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	if assert.Len(t, collection.Features, 3) {
+		properties := collection.Features[0].Properties
+		assert.Equal(t, 108.83, properties["radius_km"])
+		assert.Equal(t, []interface{}{"carpet", "tiles"}, properties["materials"])
+		assert.NotContains(t, properties, "radius")
+	}
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/tests/fixtures"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPartners counts the partner lookups reaching the repository.
+type countingPartners struct {
+	repository.PartnerRepository
+	lookups int
+}
+
+func (r *countingPartners) Get(ctx context.Context, id int16) (*models.Partner, error) {
+	r.lookups++
+	return r.PartnerRepository.Get(ctx, id)
+}
+
+func (r *countingPartners) Find(ctx context.Context, filter repository.PartnerFilter) (*repository.PartnerPage, error) {
+	r.lookups++
+	return r.PartnerRepository.Find(ctx, filter)
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage
+	Errors []struct {
+		Message    string
+		Extensions map[string]interface{}
+	}
+}
+
+func postGraphQL(t *testing.T, webApp *fiber.App, query string, variables map[string]interface{}) graphQLResponse {
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := webApp.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	var res graphQLResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	return res
+}
+
+func TestGraphQLPartnerLookupsAreBatched(t *testing.T) {
+	services := fixtures.Services(fixtures.Partners()...)
+	partners := &countingPartners{PartnerRepository: services.Partners}
+	services.Partners = partners
+	webApp := fixtures.App(services)
+
+	res := postGraphQL(t, webApp, `{
+		a: partner(id: 1) { name materials }
+		b: partner(id: 883) { name radiusKm }
+		c: partner(id: 2) { name }
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"name": "Lazz", "materials": ["carpet", "tiles"]}`, string(res.Data["a"]))
+	assert.JSONEq(t, `{"name": "Meevee", "radiusKm": 127.98}`, string(res.Data["b"]))
+	assert.JSONEq(t, `null`, string(res.Data["c"]))
+	assert.Equal(t, 1, partners.lookups)
+}
+
+func TestGraphQLPartners(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	query := `query Page($after: String) {
+		partners(filter: {materials: ["wood"], sort: RATING_DESC}, first: 1, after: $after) {
+			nodes { id }
+			pageInfo { endCursor hasNextPage }
+		}
+	}`
+	var page struct {
+		Nodes    []struct{ Id int16 }
+		PageInfo struct {
+			EndCursor   string
+			HasNextPage bool
+		}
+	}
+	res := postGraphQL(t, webApp, query, nil)
+	require.Empty(t, res.Errors)
+	require.NoError(t, json.Unmarshal(res.Data["partners"], &page))
+	assert.Equal(t, int16(805), page.Nodes[0].Id)
+	assert.True(t, page.PageInfo.HasNextPage)
+
+	res = postGraphQL(t, webApp, query, map[string]interface{}{"after": page.PageInfo.EndCursor})
+	require.Empty(t, res.Errors)
+	require.NoError(t, json.Unmarshal(res.Data["partners"], &page))
+	assert.Equal(t, int16(883), page.Nodes[0].Id)
+	assert.False(t, page.PageInfo.HasNextPage)
+
+	res = postGraphQL(t, webApp, query, map[string]interface{}{"after": "bogus"})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "BAD_USER_INPUT", res.Errors[0].Extensions["code"])
+	}
+}
+
+func TestGraphQLMatch(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	res := postGraphQL(t, webApp, `{
+		match(location: {lat: 40.076762, lng: 113.300129}, materials: ["carpet", "tiles"], sqm: 35) {
+			partner { id name }
+			distance { unit }
+		}
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `[
+		{"partner": {"id": 883, "name": "Meevee"}, "distance": {"unit": "km"}},
+		{"partner": {"id": 1, "name": "Lazz"}, "distance": {"unit": "km"}}
+	]`, string(res.Data["match"]))
+
+	res = postGraphQL(t, webApp, `{ match(location: {lat: 140, lng: 0}, materials: ["carpet"]) { score } }`, nil)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "BAD_USER_INPUT", res.Errors[0].Extensions["code"])
+		assert.Equal(t, "lat: must be between -90 and 90", res.Errors[0].Message)
+	}
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	res := postGraphQL(t, webApp, `{ partners(first: 200) { nodes { id name lat lng radiusKm rating materials minSqm maxSqm } } }`, nil)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, "query complexity")
+	}
+
+	res = postGraphQL(t, webApp, `
+		fragment Deep on Match { partner { id } distance { value } }
+		{ match(location: {lat: 0, lng: 0}, materials: ["carpet"]) { ...Deep } }`, nil)
+	assert.Empty(t, res.Errors)
+
+	// introspection is not limited, so tools can read the schema
+	res = postGraphQL(t, webApp, `{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name } } } } } } } }`, nil)
+	assert.Empty(t, res.Errors)
+}
+
+func TestGraphQLBadRequest(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	resp, _ := webApp.Test(httptest.NewRequest("GET", "/graphql", nil), -1)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, _ = webApp.Test(httptest.NewRequest("GET", "/graphql?query=%7Bpartner(id:1)%7Bname%7D%7D", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+}
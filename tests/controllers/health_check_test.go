@@ -1,15 +1,18 @@
 package controllers
 
 import (
-	"aroundHome/app"
-	"database/sql"
-	_ "github.com/lib/pq"
-	"log"
+	"aroundHome/app/health"
+	"aroundHome/app/version"
+	"aroundHome/tests/fixtures"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert" // add Testify package
+	"github.com/stretchr/testify/require"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -26,6 +29,11 @@ func TestHealthCheck(t *testing.T) {
 			route:        "/",
 			expectedCode: 200,
 		},
+		{
+			description:  "get HTTP status 200 from the liveness probe",
+			route:        "/healthz",
+			expectedCode: 200,
+		},
 		// Second test case
 		{
 			description:  "get HTTP status 404, when route is not exists",
@@ -35,15 +43,7 @@ func TestHealthCheck(t *testing.T) {
 	}
 
 	// Define Fiber webApp.
-	webApp := fiber.New()
-	db := app.DatabaseConnect()
-	defer func(db *sql.DB) {
-		err := db.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}(db)
-	app.Routes(webApp, db)
+	webApp := fixtures.App(fixtures.Services())
 
 	// Iterate through test single test cases
 	for _, test := range tests {
@@ -59,3 +59,41 @@ func TestHealthCheck(t *testing.T) {
 		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
 	}
 }
+
+func TestReadyHandler(t *testing.T) {
+	services := fixtures.Services()
+	services.Checks = []health.Check{
+		{Name: "database", Run: func(context.Context) error { return nil }},
+	}
+	resp, err := fixtures.App(services).Test(httptest.NewRequest("GET", "/readyz", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	report := new(health.Report)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(report))
+	assert.Equal(t, health.StatusOk, report.Status)
+	assert.Equal(t, health.StatusOk, report.Checks["database"].Status)
+
+	services.Checks = append(services.Checks, health.Check{
+		Name: "schema", Run: func(context.Context) error { return errors.New("database schema is outdated") },
+	})
+	resp, err = fixtures.App(services).Test(httptest.NewRequest("GET", "/readyz", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+	report = new(health.Report)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(report))
+	assert.Equal(t, health.StatusFailed, report.Status)
+	assert.Equal(t, health.StatusOk, report.Checks["database"].Status)
+	assert.Equal(t, health.Result{Status: health.StatusFailed, Error: "database schema is outdated", LatencyMs: report.Checks["schema"].LatencyMs}, report.Checks["schema"])
+}
+
+func TestVersionHandler(t *testing.T) {
+	version.Version, version.Commit = "1.4.0", "c906fcb"
+	t.Cleanup(func() { version.Version, version.Commit = "dev", "" })
+
+	resp, err := fixtures.App(fixtures.Services()).Test(httptest.NewRequest("GET", "/version", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	info := new(version.Info)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(info))
+	assert.Equal(t, version.Info{Version: "1.4.0", Commit: "c906fcb", GoVersion: runtime.Version()}, *info)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"aroundHome/app/importer"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportV1Handler(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services())
+
+	body := `{"id": 7, "name": "Flooro", "lat": 52.52, "lng": 13.405, "radius_km": 30, "rating": 7.5, "materials": ["wood"]}
+{"id": 8, "name": "Broken", "lat": 52.52, "lng": 13.405, "radius_km": 0, "rating": 7.5, "materials": ["marble"]}
+{"id": 9, "name": "Typo", "lat": 52.52, "lng": 13.405, "radius_km": "far", "rating": 7.5, "materials": ["wood"]}
+`
+	req := httptest.NewRequest("POST", "/v1/partners/import?format=ndjson", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var report importer.Report
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, 1, report.Imported)
+	// errors are reported by the fields of the v1 partners
+	fields := make([]string, 0)
+	for _, failed := range report.Failed {
+		for _, e := range failed.Errors {
+			fields = append(fields, e.Field)
+		}
+	}
+	assert.Equal(t, []string{"radius_km", "materials", "radius_km"}, fields)
+
+	resp, _ = webApp.Test(httptest.NewRequest("GET", "/v1/partners/7", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	var partner map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&partner))
+	assert.Equal(t, 30.0, partner["radius_km"])
+	assert.Equal(t, []interface{}{"wood"}, partner["materials"])
+
+	// the v1 import does not know the fields of the unversioned one
+	csv := "id,name,lat,lng,radius,rating,flooring_experience\n10,Lazz,40.076762,113.300129,108.83,0.96,\"['carpet']\"\n"
+	req = httptest.NewRequest("POST", "/v1/partners/import?format=csv", strings.NewReader(csv))
+	req.Header.Set("Content-Type", "text/plain")
+	resp, _ = webApp.Test(req, -1)
+	assert.Equal(t, 400, resp.StatusCode)
+}
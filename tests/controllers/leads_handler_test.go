@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeadsHandlers(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	body := `{"materials": ["carpet", "tiles"], "lat": 40.076762, "lng": 113.300129, "sqm": 35, "phone": "0160153700132"}`
+	req := httptest.NewRequest("POST", "/requests", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := webApp.Test(req, -1)
+	require.Equal(t, 201, resp.StatusCode)
+
+	requestLeads := func() []models.Lead {
+		resp, _ := webApp.Test(httptest.NewRequest("GET", "/requests/1/leads", nil), -1)
+		require.Equal(t, 200, resp.StatusCode)
+		recs := make([]models.Lead, 0)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&recs))
+		return recs
+	}
+
+	// the request is offered to the best ranked partner only
+	recs := requestLeads()
+	require.Len(t, recs, 1)
+	assert.Equal(t, int16(883), recs[0].PartnerId)
+	assert.Equal(t, models.LeadOffered, recs[0].State)
+
+	tests := []struct {
+		description   string
+		route         string
+		expectedCode  int
+		expectedState models.LeadState
+	}{
+		{description: "declining offers the next partner", route: "/leads/1/decline", expectedCode: 200, expectedState: models.LeadDeclined},
+		{description: "declined leads are final", route: "/leads/1/accept", expectedCode: 409},
+		{description: "the next partner views", route: "/leads/2/view", expectedCode: 200, expectedState: models.LeadViewed},
+		{description: "viewing twice", route: "/leads/2/view", expectedCode: 409},
+		{description: "viewed leads can be accepted", route: "/leads/2/accept", expectedCode: 200, expectedState: models.LeadAccepted},
+		{description: "accepted leads are final", route: "/leads/2/decline", expectedCode: 409},
+		{description: "unknown lead", route: "/leads/3/accept", expectedCode: 404},
+		{description: "invalid id", route: "/leads/x/accept", expectedCode: 400},
+	}
+	for _, test := range tests {
+		resp, _ := webApp.Test(httptest.NewRequest("POST", test.route, nil), -1)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		if test.expectedCode != 200 {
+			continue
+		}
+		rec := new(models.Lead)
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(rec))
+		assert.Equalf(t, test.expectedState, rec.State, test.description)
+	}
+
+	recs = requestLeads()
+	require.Len(t, recs, 2)
+	assert.Equal(t, int16(1), recs[1].PartnerId)
+	assert.Equal(t, 2, recs[1].Rank)
+
+	resp, _ = webApp.Test(httptest.NewRequest("GET", "/leads/2", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	resp, _ = webApp.Test(httptest.NewRequest("GET", "/requests/2/leads", nil), -1)
+	assert.Equal(t, 404, resp.StatusCode)
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"aroundHome/tests/fixtures"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterialsHandlers(t *testing.T) {
+	tests := []struct {
+		description  string
+		method       string
+		route        string
+		body         string
+		expectedCode int
+	}{
+		{
+			description:  "materials of the catalog can be queried",
+			method:       "GET",
+			route:        "/query/?address=40.076762,113.300129&material=cement",
+			expectedCode: 200,
+		},
+		{
+			description:  "unknown materials are rejected",
+			method:       "GET",
+			route:        "/query/?address=40.076762,113.300129&material=vinyl",
+			expectedCode: 400,
+		},
+		{
+			description:  "categories cannot be queried",
+			method:       "GET",
+			route:        "/query/?address=40.076762,113.300129&material=hard_floors",
+			expectedCode: 400,
+		},
+		{
+			description:  "add a material to a category",
+			method:       "PUT",
+			route:        "/admin/materials/vinyl",
+			body:         `{"Parent": "hard_floors", "Names": {"en": "Vinyl", "de": "Vinyl"}, "Active": true}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "added materials can be queried without a redeploy",
+			method:       "GET",
+			route:        "/query/?address=40.076762,113.300129&material=vinyl",
+			expectedCode: 200,
+		},
+		{
+			description:  "materials need a name",
+			method:       "PUT",
+			route:        "/admin/materials/linoleum",
+			body:         `{"Parent": "hard_floors", "Active": true}`,
+			expectedCode: 422,
+		},
+		{
+			description:  "deactivate a material",
+			method:       "PUT",
+			route:        "/admin/materials/vinyl",
+			body:         `{"Parent": "hard_floors", "Names": {"en": "Vinyl"}, "Active": false}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "inactive materials cannot be queried",
+			method:       "GET",
+			route:        "/query/?address=40.076762,113.300129&material=vinyl",
+			expectedCode: 400,
+		},
+		{
+			description:  "categories with materials cannot be deleted",
+			method:       "DELETE",
+			route:        "/admin/materials/hard_floors",
+			expectedCode: 409,
+		},
+		{
+			description:  "delete a material",
+			method:       "DELETE",
+			route:        "/admin/materials/vinyl",
+			expectedCode: 204,
+		},
+		{
+			description:  "get HTTP status 404, when material does not exist",
+			method:       "GET",
+			route:        "/admin/materials/vinyl",
+			expectedCode: 404,
+		},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.route, strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+	}
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"aroundHome/app/openapi"
+	"aroundHome/app/problem"
+	"aroundHome/app/validation"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var routeParam = regexp.MustCompile(`:(\w+)`)
+
+// TestOpenAPICoversRoutes fails when a route is added without annotations or an
+// annotated operation has no route, run swag init after changing either.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	var documented []string
+	for path, item := range doc.Paths {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	var registered []string
+	seen := map[string]bool{}
+	for _, routes := range fixtures.App(fixtures.Services()).Stack() {
+		for _, route := range routes {
+			// HEAD comes with GET, middleware is mounted at / for every method
+			if route.Method == "HEAD" || route.Path == "/" && route.Method != "GET" {
+				continue
+			}
+			if strings.HasPrefix(route.Path, "/swagger/") {
+				continue
+			}
+			key := route.Method + " " + routeParam.ReplaceAllString(route.Path, "{$1}")
+			if !seen[key] {
+				seen[key] = true
+				registered = append(registered, key)
+			}
+		}
+	}
+
+	sort.Strings(documented)
+	sort.Strings(registered)
+	assert.Equal(t, documented, registered)
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services())
+
+	resp, err := webApp.Test(httptest.NewRequest("GET", "/openapi.json", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	doc := new(openapi3.T)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	if assert.NotNil(t, doc.Paths.Find("/partners/{id}")) {
+		assert.True(t, doc.Paths.Find("/partners/{id}").Get.Deprecated)
+	}
+	if assert.NotNil(t, doc.Paths.Find("/v1/partners/{id}")) {
+		assert.False(t, doc.Paths.Find("/v1/partners/{id}").Get.Deprecated)
+	}
+}
+
+func TestOpenAPIValidation(t *testing.T) {
+	tests := []struct {
+		description    string
+		method         string
+		route          string
+		body           string
+		expectedCode   int
+		expectedFields validation.Errors
+	}{
+		{
+			description:    "path parameter of the wrong type",
+			method:         "GET",
+			route:          "/v1/partners/abc",
+			expectedCode:   400,
+			expectedFields: validation.Errors{{Field: "id", Message: "must be an integer"}},
+		},
+		{
+			description:    "query parameter not in the enum",
+			method:         "GET",
+			route:          "/v1/partners?sort=size",
+			expectedCode:   400,
+			expectedFields: validation.Errors{{Field: "sort", Message: "must be one of id, name, rating, -rating"}},
+		},
+		{
+			description:    "list of the wrong type",
+			method:         "GET",
+			route:          "/v1/partners?ids=1,x",
+			expectedCode:   400,
+			expectedFields: validation.Errors{{Field: "ids", Message: "must be a list of integers"}},
+		},
+		{
+			description:    "body field of the wrong type",
+			method:         "POST",
+			route:          "/v1/partners",
+			body:           `{"name": "Kwideo", "lat": 52.52, "lng": 13.405, "radius_km": 50, "rating": "high", "materials": ["wood"]}`,
+			expectedCode:   422,
+			expectedFields: validation.Errors{{Field: "rating", Message: "must be a number"}},
+		},
+		{
+			description:  "body that is no JSON",
+			method:       "POST",
+			route:        "/v1/partners",
+			body:         `{"name": `,
+			expectedCode: 400,
+		},
+		{
+			description:    "ranges are left to the handler",
+			method:         "POST",
+			route:          "/v1/partners",
+			body:           `{"name": "Kwideo", "lat": 52.52, "lng": 13.405, "radius_km": 50, "rating": 11, "materials": ["wood"]}`,
+			expectedCode:   422,
+			expectedFields: validation.Errors{{Field: "rating", Message: "must be between 0 and 10"}},
+		},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.route, strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := webApp.Test(req, -1)
+		if !assert.NoErrorf(t, err, test.description) {
+			continue
+		}
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+
+		body := new(problem.Problem)
+		assert.NoErrorf(t, json.NewDecoder(resp.Body).Decode(body), test.description)
+		if test.expectedFields != nil {
+			assert.Equalf(t, test.expectedFields, body.Errors, test.description)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/validation"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartnersHandler(t *testing.T) {
+	tests := []struct {
+		description  string
+		route        string
+		expectedCode int
+		expectedName string
+	}{
+		{
+			description:  "get existing partner",
+			route:        "/partners/883",
+			expectedCode: 200,
+			expectedName: "Meevee",
+		},
+		{
+			description:  "get HTTP status 404, when partner does not exist",
+			route:        "/partners/2",
+			expectedCode: 404,
+		},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", test.route, nil)
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		if test.expectedName == "" {
+			continue
+		}
+		partner := new(models.Partner)
+		assert.NoErrorf(t, json.NewDecoder(resp.Body).Decode(partner), test.description)
+		assert.Equalf(t, test.expectedName, partner.Name, test.description)
+	}
+}
+
+func TestPartnerCrudHandlers(t *testing.T) {
+	tests := []struct {
+		description  string
+		method       string
+		route        string
+		body         string
+		expectedCode int
+		expectedName string
+	}{
+		{
+			description:  "create a partner with the next free id",
+			method:       "POST",
+			route:        "/partners",
+			body:         `{"Name": "Kwideo", "Lat": 52.52, "Lng": 13.405, "Radius": 50, "Rating": 7.5, "FlooringExperience": "{cement,wood}"}`,
+			expectedCode: 201,
+			expectedName: "Kwideo",
+		},
+		{
+			description:  "created partners can be read",
+			method:       "GET",
+			route:        "/partners/884",
+			expectedCode: 200,
+			expectedName: "Kwideo",
+		},
+		{
+			description:  "ids are unique",
+			method:       "POST",
+			route:        "/partners",
+			body:         `{"Id": 884, "Name": "Kwideo", "Lat": 52.52, "Lng": 13.405, "Radius": 50, "Rating": 7.5, "FlooringExperience": "{wood}"}`,
+			expectedCode: 409,
+		},
+		{
+			description:  "invalid partners are rejected",
+			method:       "POST",
+			route:        "/partners",
+			body:         `{"Name": "Kwideo", "Lat": 152.52, "Lng": 13.405, "Rating": 11, "FlooringExperience": "{vinyl}"}`,
+			expectedCode: 422,
+		},
+		{
+			description:  "replace a partner",
+			method:       "PUT",
+			route:        "/partners/884",
+			body:         `{"Name": "Kwideo Berlin", "Lat": 52.52, "Lng": 13.405, "Radius": 60, "Rating": 8, "FlooringExperience": "{wood}"}`,
+			expectedCode: 200,
+			expectedName: "Kwideo Berlin",
+		},
+		{
+			description:  "replaced partners are validated",
+			method:       "PUT",
+			route:        "/partners/884",
+			body:         `{"Name": "Kwideo Berlin", "Lat": 52.52, "Lng": 13.405, "Rating": 8, "FlooringExperience": "{wood}"}`,
+			expectedCode: 422,
+		},
+		{
+			description:  "replacing needs an existing partner",
+			method:       "PUT",
+			route:        "/partners/2",
+			body:         `{"Name": "Kwideo Berlin", "Lat": 52.52, "Lng": 13.405, "Radius": 60, "Rating": 8, "FlooringExperience": "{wood}"}`,
+			expectedCode: 404,
+		},
+		{
+			description:  "patch keeps the fields missing from the body",
+			method:       "PATCH",
+			route:        "/partners/883",
+			body:         `{"Rating": 6}`,
+			expectedCode: 200,
+			expectedName: "Meevee",
+		},
+		{
+			description:  "patched partners are validated",
+			method:       "PATCH",
+			route:        "/partners/883",
+			body:         `{"Rating": -1}`,
+			expectedCode: 422,
+		},
+		{
+			description:  "delete a partner",
+			method:       "DELETE",
+			route:        "/partners/884",
+			expectedCode: 204,
+		},
+		{
+			description:  "deleted partners are gone",
+			method:       "GET",
+			route:        "/partners/884",
+			expectedCode: 404,
+		},
+		{
+			description:  "deleting needs an existing partner",
+			method:       "DELETE",
+			route:        "/partners/884",
+			expectedCode: 404,
+		},
+		{
+			description:  "ids are integers",
+			method:       "DELETE",
+			route:        "/partners/x",
+			expectedCode: 400,
+		},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.route, strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		if test.expectedName == "" {
+			continue
+		}
+		partner := new(models.Partner)
+		assert.NoErrorf(t, json.NewDecoder(resp.Body).Decode(partner), test.description)
+		assert.Equalf(t, test.expectedName, partner.Name, test.description)
+	}
+
+	req := httptest.NewRequest("GET", "/partners/883", nil)
+	resp, _ := webApp.Test(req, -1)
+	partner := new(models.Partner)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(partner))
+	assert.Equal(t, float32(6), partner.Rating)
+	assert.Equal(t, float32(127.98), partner.Radius)
+}
+
+func TestPartnerValidationErrors(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	body := `{"Name": " ", "Lat": 152.52, "Lng": 13.405, "Radius": 0, "Rating": 11, "FlooringExperience": "{carpet,vinyl}"}`
+	req := httptest.NewRequest("POST", "/partners", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 422, resp.StatusCode)
+
+	var result struct {
+		Errors validation.Errors `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	fields := make([]string, 0)
+	for _, e := range result.Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"name", "lat", "radius", "rating", "flooring_experience"}, fields)
+}
+
+type partnerList struct {
+	Partners   []models.Partner `json:"partners"`
+	NextCursor string           `json:"next_cursor"`
+}
+
+func listPartners(t *testing.T, webApp *fiber.App, route string) (int, partnerList) {
+	resp, _ := webApp.Test(httptest.NewRequest("GET", route, nil), -1)
+	var list partnerList
+	if resp.StatusCode == 200 {
+		assert.NoErrorf(t, json.NewDecoder(resp.Body).Decode(&list), route)
+	}
+	return resp.StatusCode, list
+}
+
+func partnerIds(list partnerList) []int16 {
+	ids := make([]int16, 0)
+	for _, p := range list.Partners {
+		ids = append(ids, p.Id)
+	}
+	return ids
+}
+
+func TestListPartnersHandler(t *testing.T) {
+	tests := []struct {
+		description  string
+		route        string
+		expectedCode int
+		expectedIds  []int16
+	}{
+		{description: "all partners by id", route: "/partners", expectedCode: 200, expectedIds: []int16{1, 805, 883}},
+		{description: "sort by name", route: "/partners?sort=name", expectedCode: 200, expectedIds: []int16{805, 1, 883}},
+		{description: "sort by rating", route: "/partners?sort=rating", expectedCode: 200, expectedIds: []int16{1, 883, 805}},
+		{description: "best rated first", route: "/partners?sort=-rating", expectedCode: 200, expectedIds: []int16{805, 883, 1}},
+		{description: "all materials", route: "/partners?materials=carpet,wood", expectedCode: 200, expectedIds: []int16{805, 883}},
+		{description: "any material", route: "/partners?materials_any=wood,cement", expectedCode: 200, expectedIds: []int16{805, 883}},
+		{description: "rating range", route: "/partners?min_rating=1&max_rating=9", expectedCode: 200, expectedIds: []int16{883}},
+		{description: "name prefix ignoring case", route: "/partners?name=mee", expectedCode: 200, expectedIds: []int16{883}},
+		{description: "bounding box", route: "/partners?bbox=110,35,120,45", expectedCode: 200, expectedIds: []int16{1, 883}},
+		{description: "bounding box across the antimeridian", route: "/partners?bbox=170,35,115,45", expectedCode: 200, expectedIds: []int16{1, 883}},
+		{description: "bulk lookup by ids", route: "/partners?ids=883,1,2", expectedCode: 200, expectedIds: []int16{1, 883}},
+		{description: "limit is capped", route: "/partners?limit=1000", expectedCode: 200, expectedIds: []int16{1, 805, 883}},
+		{description: "limit must be positive", route: "/partners?limit=0", expectedCode: 400},
+		{description: "unknown sort", route: "/partners?sort=distance", expectedCode: 400},
+		{description: "invalid cursor", route: "/partners?cursor=abc", expectedCode: 400},
+		{description: "invalid ids", route: "/partners?ids=1,x", expectedCode: 400},
+		{description: "invalid rating", route: "/partners?min_rating=high", expectedCode: 400},
+		{description: "invalid bounding box", route: "/partners?bbox=110,35,120", expectedCode: 400},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		code, list := listPartners(t, webApp, test.route)
+		assert.Equalf(t, test.expectedCode, code, test.description)
+		if test.expectedCode == 200 {
+			assert.Equalf(t, test.expectedIds, partnerIds(list), test.description)
+			assert.Emptyf(t, list.NextCursor, test.description)
+		}
+	}
+}
+
+func TestListPartnersPagination(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, sort := range []string{"id", "name", "rating", "-rating"} {
+		_, all := listPartners(t, webApp, "/partners?sort="+sort)
+		ids := make([]int16, 0)
+		route := "/partners?limit=1&sort=" + sort
+		for {
+			code, page := listPartners(t, webApp, route)
+			if !assert.Equal(t, 200, code, route) {
+				break
+			}
+			ids = append(ids, partnerIds(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			route = "/partners?limit=1&sort=" + sort + "&cursor=" + page.NextCursor
+		}
+		assert.Equalf(t, partnerIds(all), ids, "pages sorted by %s", sort)
+	}
+
+	// cursors only continue the sort they were issued for
+	_, page := listPartners(t, webApp, "/partners?limit=1")
+	code, _ := listPartners(t, webApp, "/partners?limit=1&sort=name&cursor="+page.NextCursor)
+	assert.Equal(t, 400, code)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHandler(t *testing.T) {
+	tests := []struct {
+		description  string
+		route        string
+		expectedCode int
+		expectedIds  []int16
+	}{
+		{
+			description:  "partners ordered by rating, then distance",
+			route:        "/query/?address=40.076762,113.300129&material=carpet,tiles&phone=0160153700132&sqm=35",
+			expectedCode: 200,
+			expectedIds:  []int16{883, 1},
+		},
+		{
+			description:  "partners must offer every requested material",
+			route:        "/query/?address=40.076762,113.300129&material=wood",
+			expectedCode: 200,
+			expectedIds:  []int16{883},
+		},
+		{
+			description:  "no partners outside of the operating radius",
+			route:        "/query/?address=0,0&material=carpet",
+			expectedCode: 200,
+			expectedIds:  []int16{},
+		},
+	}
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", test.route, nil)
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+
+		var body struct {
+			Partners []struct {
+				Partner struct{ Id int16 }
+			}
+		}
+		assert.NoErrorf(t, json.NewDecoder(resp.Body).Decode(&body), test.description)
+		ids := make([]int16, 0)
+		for _, p := range body.Partners {
+			ids = append(ids, p.Partner.Id)
+		}
+		assert.Equalf(t, test.expectedIds, ids, test.description)
+	}
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestsHandlers(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	invalid := []struct {
+		description string
+		body        string
+	}{
+		{description: "phone is required", body: `{"materials": ["carpet"], "lat": 40.076762, "lng": 113.300129, "sqm": 35}`},
+		{description: "materials are required", body: `{"lat": 40.076762, "lng": 113.300129, "sqm": 35, "phone": "0160153700132"}`},
+		{description: "latitude must be in range", body: `{"materials": ["carpet"], "lat": 140, "lng": 113.300129, "phone": "0160153700132"}`},
+	}
+	for _, test := range invalid {
+		req := httptest.NewRequest("POST", "/requests", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, 422, resp.StatusCode, test.description)
+	}
+
+	body := `{"materials": ["carpet", "tiles"], "lat": 40.076762, "lng": 113.300129, "sqm": 35, "phone": "0160153700132"}`
+	req := httptest.NewRequest("POST", "/requests", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, "/requests/1", resp.Header.Get("Location"))
+
+	req = httptest.NewRequest("GET", "/requests/1", nil)
+	resp, _ = webApp.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	stored := new(models.CustomerRequest)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(stored))
+	assert.Equal(t, "0160153700132", stored.Phone)
+	assert.Equal(t, float32(35), stored.Sqm)
+	assert.False(t, stored.CreatedAt.IsZero())
+	if assert.Len(t, stored.Matches, 2) {
+		assert.Equal(t, int16(883), stored.Matches[0].Partner.Id)
+		assert.Equal(t, int16(1), stored.Matches[1].Partner.Id)
+	}
+
+	req = httptest.NewRequest("GET", "/requests/2", nil)
+	resp, _ = webApp.Test(req, -1)
+	assert.Equal(t, 404, resp.StatusCode)
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"aroundHome/app/problem"
+	"aroundHome/tests/fixtures"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV1Partner(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	resp, _ := webApp.Test(httptest.NewRequest("GET", "/v1/partners/1", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Deprecation"))
+	var body map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{
+		"id":        float64(1),
+		"name":      "Lazz",
+		"lat":       40.076763,
+		"lng":       113.30013,
+		"radius_km": 108.83,
+		"rating":    0.96,
+		"materials": []interface{}{"carpet", "tiles"},
+		"min_sqm":   float64(0),
+		"max_sqm":   float64(0),
+	}, body)
+}
+
+func TestV1Query(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	resp, _ := webApp.Test(httptest.NewRequest("GET", "/v1/query?address=40.076762,113.300129&material=carpet,tiles&sqm=35", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Sqm     float64
+		Matches []struct {
+			Id        int16
+			Materials []string
+			Distance  struct {
+				Value float64
+				Unit  string
+			}
+		}
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, float64(35), body.Sqm)
+	if assert.Len(t, body.Matches, 2) {
+		assert.Equal(t, int16(883), body.Matches[0].Id)
+		assert.Equal(t, []string{"carpet", "tiles", "wood"}, body.Matches[0].Materials)
+		assert.Equal(t, "km", body.Matches[0].Distance.Unit)
+		assert.Greater(t, body.Matches[0].Distance.Value, float64(0))
+		assert.Equal(t, int16(1), body.Matches[1].Id)
+		// the address is parsed as float64, the office is stored as float32
+		assert.InDelta(t, 0, body.Matches[1].Distance.Value, 0.001)
+	}
+}
+
+func TestV1QueryBatch(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	batch := `{"requests": [
+		{"id": "a", "lat": 40.076762, "lng": 113.300129, "materials": ["carpet", "tiles"], "sqm": 35},
+		{"id": "b", "lat": 49.6087627, "lng": 18.4861804, "materials": ["wood"]},
+		{"id": "c", "lat": 40.076762, "lng": 113.300129, "materials": ["vinyl"]},
+		{"id": "d", "lat": 0, "lng": 0, "materials": ["carpet"]}
+	]}`
+	req := httptest.NewRequest("POST", "/v1/query/batch", strings.NewReader(batch))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Results map[string]struct {
+			Matches *[]struct{ Id int16 }
+			Error   *problem.Problem
+		}
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	ids := func(id string) []int16 {
+		result := body.Results[id]
+		if !assert.NotNilf(t, result.Matches, id) {
+			return nil
+		}
+		ids := make([]int16, 0)
+		for _, m := range *result.Matches {
+			ids = append(ids, m.Id)
+		}
+		return ids
+	}
+	assert.Len(t, body.Results, 4)
+	assert.Equal(t, []int16{883, 1}, ids("a"))
+	assert.Equal(t, []int16{805}, ids("b"))
+	assert.Equal(t, []int16{}, ids("d"))
+	if c := body.Results["c"]; assert.NotNil(t, c.Error) {
+		assert.Nil(t, c.Matches)
+		assert.Equal(t, 422, c.Error.Status)
+		assert.Equal(t, "materials", c.Error.Errors[0].Field)
+	}
+
+	// the ids of the oversized batch are unique, so only its size is invalid
+	tooMany := make([]string, 1001)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf(`{"id": "q%d"}`, i)
+	}
+	invalid := []struct {
+		description string
+		body        string
+		field       string
+	}{
+		{description: "empty batch", body: `{"requests": []}`, field: "requests"},
+		{description: "missing id", body: `{"requests": [{"lat": 1, "lng": 1, "materials": ["carpet"]}]}`, field: "requests.0.id"},
+		{description: "duplicate id", body: `{"requests": [{"id": "a", "materials": ["carpet"]}, {"id": "a", "materials": ["wood"]}]}`, field: "requests.1.id"},
+		{description: "too many queries", body: `{"requests": [` + strings.Join(tooMany, ",") + `]}`, field: "requests"},
+	}
+	for _, test := range invalid {
+		req := httptest.NewRequest("POST", "/v1/query/batch", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := webApp.Test(req, -1)
+		assert.Equalf(t, 422, resp.StatusCode, test.description)
+		var p problem.Problem
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+		if assert.Lenf(t, p.Errors, 1, test.description) {
+			assert.Equalf(t, test.field, p.Errors[0].Field, test.description)
+		}
+	}
+}
+
+func TestV1PartnerCrud(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	body := `{"name": "Flooro", "lat": 52.52, "lng": 13.405, "radius_km": 30, "rating": 7.5, "materials": ["wood"]}`
+	req := httptest.NewRequest("POST", "/v1/partners", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, "/v1/partners/884", resp.Header.Get("Location"))
+
+	req = httptest.NewRequest("PATCH", "/v1/partners/884", strings.NewReader(`{"radius_km": 45}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = webApp.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	var patched struct {
+		Name      string
+		RadiusKm  float64 `json:"radius_km"`
+		Materials []string
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&patched))
+	assert.Equal(t, "Flooro", patched.Name)
+	assert.Equal(t, float64(45), patched.RadiusKm)
+	assert.Equal(t, []string{"wood"}, patched.Materials)
+
+	req = httptest.NewRequest("PUT", "/v1/partners/884", strings.NewReader(`{"name": "Flooro", "lat": 52.52, "lng": 13.405, "radius_km": -1, "rating": 7.5, "materials": ["vinyl"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = webApp.Test(req, -1)
+	assert.Equal(t, 422, resp.StatusCode)
+	var invalid struct {
+		Errors []struct{ Field string }
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&invalid))
+	fields := make([]string, 0)
+	for _, e := range invalid.Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"radius_km", "materials"}, fields)
+}
+
+func TestV1Requests(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	body := `{"materials": ["carpet", "tiles"], "lat": 40.076762, "lng": 113.300129, "sqm": 35, "phone": "0160153700132"}`
+	req := httptest.NewRequest("POST", "/v1/requests", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := webApp.Test(req, -1)
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, "/v1/requests/1", resp.Header.Get("Location"))
+
+	resp, _ = webApp.Test(httptest.NewRequest("GET", "/v1/requests/1/leads", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	var leads []map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&leads))
+	if assert.Len(t, leads, 1) {
+		assert.Equal(t, float64(883), leads[0]["partner_id"])
+		assert.Equal(t, "offered", leads[0]["state"])
+		assert.Contains(t, leads[0], "expires_at")
+	}
+}
+
+func TestDeprecatedRoutes(t *testing.T) {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...))
+
+	tests := []struct {
+		route     string
+		successor string
+	}{
+		{route: "/partners/1", successor: "</v1/partners/1>; rel=\"successor-version\""},
+		{route: "/partners/2", successor: "</v1/partners/2>; rel=\"successor-version\""},
+		{route: "/query/?address=0,0&material=carpet", successor: "</v1/query>; rel=\"successor-version\""},
+		{route: "/admin/materials", successor: "</v1/admin/materials>; rel=\"successor-version\""},
+	}
+	for _, test := range tests {
+		resp, _ := webApp.Test(httptest.NewRequest("GET", test.route, nil), -1)
+		assert.Equalf(t, "true", resp.Header.Get("Deprecation"), test.route)
+		assert.Equalf(t, test.successor, resp.Header.Get("Link"), test.route)
+	}
+}
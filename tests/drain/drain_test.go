@@ -0,0 +1,212 @@
+package drain
+
+import (
+	"aroundHome/app/controllers"
+	"aroundHome/app/drain"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servers are a web and a gRPC server on real listeners, tracked by tracker.
+type servers struct {
+	tracker      *drain.Tracker
+	webApp       *fiber.App
+	webListener  net.Listener
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+	// started receives a value when a request to /slow or /export started
+	started chan struct{}
+}
+
+// stallingPartners export one partner and then wait for the context.
+type stallingPartners struct {
+	*repository.Memory
+	started chan struct{}
+}
+
+func (p *stallingPartners) Each(ctx context.Context, fn func(*models.Partner) error) error {
+	p.started <- struct{}{}
+	if err := fn(&models.Partner{Id: 1, Name: "Lazz", FlooringExperience: "{carpet}"}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// newServers serves /slow, which takes delay unless its context is done
+// first, and /export, which streams until its context is done.
+func newServers(t *testing.T, delay time.Duration) *servers {
+	s := &servers{tracker: drain.New(), started: make(chan struct{}, 1)}
+	s.webApp = fiber.New(fiber.Config{DisableStartupMessage: true})
+	s.webApp.Use(s.tracker.Middleware())
+	s.webApp.Get("/slow", func(c *fiber.Ctx) error {
+		s.started <- struct{}{}
+		select {
+		case <-time.After(delay):
+			return c.SendString("done")
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		}
+	})
+	partners := &stallingPartners{Memory: repository.NewMemory(), started: s.started}
+	s.webApp.Get("/export", func(c *fiber.Ctx) error {
+		return controllers.ExportHandler(c, partners)
+	})
+	s.grpcServer = grpc.NewServer(s.tracker.ServerOptions()...)
+	healthpb.RegisterHealthServer(s.grpcServer, health.NewServer())
+
+	var err error
+	s.webListener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s.grpcListener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return s
+}
+
+// serve serves until the servers are shut down.
+func (s *servers) serve() {
+	go s.grpcServer.Serve(s.grpcListener)
+	go s.webApp.Listener(s.webListener)
+}
+
+type response struct {
+	status int
+	body   string
+	err    error
+}
+
+// get requests path and returns the response once the request started.
+func (s *servers) get(t *testing.T, path string) <-chan response {
+	responses := make(chan response, 1)
+	go func() {
+		client := &http.Client{Transport: &http.Transport{}}
+		resp, err := client.Get("http://" + s.webListener.Addr().String() + path)
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- response{status: resp.StatusCode, body: string(body), err: err}
+	}()
+	select {
+	case <-s.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not start")
+	}
+	return responses
+}
+
+func TestShutdownDrainsRequests(t *testing.T) {
+	s := newServers(t, 200*time.Millisecond)
+	s.serve()
+	responses := s.get(t, "/slow")
+
+	start := time.Now()
+	assert.NoError(t, s.tracker.Shutdown(s.webApp, s.grpcServer, 5*time.Second))
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	resp := <-responses
+	assert.NoError(t, resp.err)
+	assert.Equal(t, 200, resp.status)
+	assert.Equal(t, "done", resp.body)
+
+	// new connections are refused
+	_, err := http.Get("http://" + s.webListener.Addr().String() + "/slow")
+	assert.Error(t, err)
+}
+
+func TestShutdownDropsRequestsAfterTimeout(t *testing.T) {
+	s := newServers(t, time.Minute)
+	s.serve()
+	responses := s.get(t, "/slow")
+
+	// a gRPC stream running until it is cancelled
+	conn, err := grpc.Dial(s.grpcListener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	watch, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = s.tracker.Shutdown(s.webApp, s.grpcServer, 100*time.Millisecond)
+	assert.True(t, errors.Is(err, drain.ErrTimeout), "error %v", err)
+	assert.Contains(t, err.Error(), "2 after 100ms")
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// the connection of the request is closed without a response
+	resp := <-responses
+	assert.Error(t, resp.err)
+	_, err = watch.Recv()
+	assert.Error(t, err)
+}
+
+func TestRunShutsDownOnSignal(t *testing.T) {
+	s := newServers(t, 200*time.Millisecond)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.tracker.Run(ctx, s.webApp, s.webListener, s.grpcServer, s.grpcListener, 5*time.Second)
+	}()
+	responses := s.get(t, "/slow")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the signal")
+	}
+	resp := <-responses
+	assert.NoError(t, resp.err)
+	assert.Equal(t, "done", resp.body)
+}
+
+func TestRunReturnsServerErrors(t *testing.T) {
+	s := newServers(t, 0)
+	// the gRPC server fails to serve on a closed listener
+	s.grpcListener.Close()
+
+	err := s.tracker.Run(context.Background(), s.webApp, s.webListener, s.grpcServer, s.grpcListener, time.Second)
+	assert.Error(t, err)
+	_, err = http.Get("http://" + s.webListener.Addr().String() + "/slow")
+	assert.Error(t, err)
+}
+
+func TestShutdownDropsExportsAfterTimeout(t *testing.T) {
+	s := newServers(t, 0)
+	s.serve()
+	// the body of the export is streamed after its handler returned
+	responses := s.get(t, "/export")
+
+	start := time.Now()
+	err := s.tracker.Shutdown(s.webApp, s.grpcServer, 100*time.Millisecond)
+	assert.True(t, errors.Is(err, drain.ErrTimeout), "error %v", err)
+	assert.Contains(t, err.Error(), "1 after 100ms")
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	resp := <-responses
+	assert.Error(t, resp.err)
+}
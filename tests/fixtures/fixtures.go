@@ -0,0 +1,57 @@
+// Package fixtures wires the services of the server with in-memory
+// repositories for the tests, like serve wires them with PostgreSQL.
+package fixtures
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/leads"
+	"aroundHome/app/matching"
+	"aroundHome/app/models"
+	"aroundHome/app/problem"
+	"aroundHome/app/ranking"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// Partners returns Lazz and Meevee near each other in China and Blogtags in
+// Poland, a new copy on every call.
+func Partners() []*models.Partner {
+	return []*models.Partner{
+		{Id: 1, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"},
+		{Id: 883, Name: "Meevee", Lat: 39.296173, Lng: 113.690698, Radius: 127.98, Rating: 5.25, FlooringExperience: "{carpet,tiles,wood}"},
+		{Id: 805, Name: "Blogtags", Lat: 49.6087627, Lng: 18.4861804, Radius: 100, Rating: 9.99, FlooringExperience: "{carpet,tiles,wood}"},
+	}
+}
+
+// Services wires the routes with in-memory repositories holding partners and the default materials.
+func Services(partners ...*models.Partner) app.Services {
+	repo := repository.NewMemory(partners...)
+	materials := taxonomy.NewStore(repository.NewMemoryMaterials(taxonomy.Defaults()...), taxonomy.DefaultTTL)
+	requests := repository.NewMemoryRequests()
+	leadRepository := repository.NewMemoryLeads()
+	return app.Services{
+		Partners:   repo,
+		Materials:  materials,
+		Matcher:    &matching.Service{Partners: repo, Materials: materials, Ranker: ranking.Default{}},
+		Requests:   requests,
+		Leads:      leadRepository,
+		Dispatcher: &leads.Dispatcher{Leads: leadRepository, Requests: requests, Offers: 1, Accepts: 1, TTL: leads.DefaultTTL},
+	}
+}
+
+// App returns an app configured like serve with the routes wired to services.
+// The observing middleware, like the access log, runs first in the given
+// order, followed by the error handling and recover of serve.
+func App(services app.Services, middleware ...fiber.Handler) *fiber.App {
+	webApp := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	for _, handler := range middleware {
+		webApp.Use(handler)
+	}
+	webApp.Use(problem.Middleware())
+	webApp.Use(recover.New())
+	app.Routes(webApp, services)
+	return webApp
+}
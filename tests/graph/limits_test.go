@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"aroundHome/app/graph"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits(t *testing.T) {
+	schema := graph.New(repository.NewMemory(
+		&models.Partner{Id: 1, Name: "Lazz", FlooringExperience: "{carpet,tiles}"},
+	), nil)
+
+	tests := []struct {
+		description string
+		limits      graph.Limits
+		query       string
+		variables   map[string]interface{}
+		expected    string
+	}{
+		{
+			description: "within limits",
+			limits:      graph.Limits{MaxDepth: 2, MaxComplexity: 5},
+			query:       `{ partner(id: 1) { name } }`,
+		},
+		{
+			description: "too deep",
+			limits:      graph.Limits{MaxDepth: 2, MaxComplexity: 100},
+			query:       `{ partners(first: 1) { pageInfo { hasNextPage } } }`,
+			expected:    "query depth 3 exceeds the limit of 2",
+		},
+		{
+			description: "too deep through a fragment",
+			limits:      graph.Limits{MaxDepth: 2, MaxComplexity: 100},
+			query:       `fragment P on PartnerConnection { pageInfo { endCursor } } { partners(first: 1) { ...P } }`,
+			expected:    "query depth 3 exceeds the limit of 2",
+		},
+		{
+			description: "every field counts",
+			limits:      graph.Limits{MaxDepth: 2, MaxComplexity: 5},
+			query:       `{ a: partner(id: 1) { id name } b: partner(id: 1) { id name } }`,
+			expected:    "query complexity 6 exceeds the limit of 5",
+		},
+		{
+			description: "lists multiply by their page size",
+			limits:      graph.Limits{MaxDepth: 3, MaxComplexity: 10},
+			query:       `{ partners(first: 5) { nodes { id name } } }`,
+			expected:    "query complexity 20 exceeds the limit of 10",
+		},
+		{
+			description: "page size from variables",
+			limits:      graph.Limits{MaxDepth: 3, MaxComplexity: 10},
+			query:       `query($n: Int) { partners(first: $n) { nodes { id } } }`,
+			variables:   map[string]interface{}{"n": 4},
+			expected:    "query complexity 12 exceeds the limit of 10",
+		},
+		{
+			description: "small page from variables",
+			limits:      graph.Limits{MaxDepth: 3, MaxComplexity: 10},
+			query:       `query($n: Int) { partners(first: $n) { nodes { id } } }`,
+			variables:   map[string]interface{}{"n": 2},
+		},
+		{
+			description: "pages larger than resolved are capped",
+			limits:      graph.Limits{MaxDepth: graph.DefaultMaxDepth, MaxComplexity: graph.DefaultMaxComplexity},
+			query:       `{ partners(first: 500) { nodes { id name rating lat lng } } }`,
+		},
+		{
+			description: "capped pages are estimated at the largest page",
+			limits:      graph.Limits{MaxDepth: 3, MaxComplexity: 1000},
+			query:       `{ partners(first: 500) { nodes { id name rating lat lng } } }`,
+			expected:    "query complexity 1400 exceeds the limit of 1000",
+		},
+	}
+	for _, test := range tests {
+		schema.Limits = test.limits
+		res := schema.Execute(context.Background(), graph.Request{Query: test.query, Variables: test.variables})
+		if test.expected == "" {
+			assert.Emptyf(t, res.Errors, test.description)
+			continue
+		}
+		if assert.Lenf(t, res.Errors, 1, test.description) {
+			assert.Equalf(t, test.expected, res.Errors[0].Message, test.description)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"aroundHome/app/health"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/app/spatial"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	hanging := health.Check{Name: "hanging", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	failing := health.Check{Name: "failing", Run: func(context.Context) error { return errors.New("connection refused") }}
+	passing := health.Check{Name: "passing", Run: func(context.Context) error { return nil }}
+
+	start := time.Now()
+	report := health.Run(context.Background(), []health.Check{hanging, failing, passing}, 50*time.Millisecond)
+	// the checks run concurrently, bounded by the timeout
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.False(t, report.Ready())
+	assert.Equal(t, health.StatusFailed, report.Status)
+	require.Len(t, report.Checks, 3)
+	assert.Equal(t, health.StatusFailed, report.Checks["hanging"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["hanging"].Error)
+	assert.Equal(t, "connection refused", report.Checks["failing"].Error)
+	assert.Equal(t, health.Result{Status: health.StatusOk, LatencyMs: report.Checks["passing"].LatencyMs}, report.Checks["passing"])
+
+	report = health.Run(context.Background(), []health.Check{passing}, time.Second)
+	assert.True(t, report.Ready())
+	report = health.Run(context.Background(), nil, time.Second)
+	assert.True(t, report.Ready())
+}
+
+// failingPartners fails to list partners once failing is set.
+type failingPartners struct {
+	*repository.Memory
+	failing bool
+}
+
+func (p *failingPartners) List(ctx context.Context) ([]*models.Partner, error) {
+	if p.failing {
+		return nil, errors.New("connection refused")
+	}
+	return p.Memory.List(ctx)
+}
+
+func TestIndex(t *testing.T) {
+	partners := &failingPartners{Memory: repository.NewMemory()}
+	indexed, err := spatial.NewIndexed(context.Background(), partners, spatial.DefaultCellSize)
+	require.NoError(t, err)
+	check := health.Index(indexed, 50*time.Millisecond)
+	report := health.Run(context.Background(), []health.Check{check}, time.Second)
+	assert.True(t, report.Ready())
+	assert.Equal(t, health.StatusOk, report.Checks["index"].Status)
+
+	// failed refreshes keep the previous index, which gets stale
+	partners.failing = true
+	time.Sleep(60 * time.Millisecond)
+	assert.Error(t, indexed.Refresh(context.Background()))
+	report = health.Run(context.Background(), []health.Check{check}, time.Second)
+	assert.False(t, report.Ready())
+	assert.Contains(t, report.Checks["index"].Error, "partner index was last refreshed at")
+
+	partners.failing = false
+	require.NoError(t, indexed.Refresh(context.Background()))
+	report = health.Run(context.Background(), []health.Check{check}, time.Second)
+	assert.True(t, report.Ready())
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"aroundHome/app/importer"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const partnersCSV = `id,name,lat,lng,radius,rating,flooring_experience
+1,Lazz,40.076762,113.300129,108.83,0.96,"['carpet','tiles']"
+9,Latz,50.8105808,27.3166945,128.72,3.48,"['carpet','cement','tiles','wood']"
+10,Broken,95,27.3166945,128.72,11,"['carpet','marble']"
+11,Unparsable,north,27.3166945,128.72,1,"['carpet'"
+`
+
+const partnersJSON = `[
+  {"id": 1, "name": "Lazz", "lat": 40.076762, "lng": 113.300129, "radius": 108.83, "rating": 0.96, "flooring_experience": ["carpet", "tiles"]},
+  {"id": 9, "name": "Latz", "lat": 50.8105808, "lng": 27.3166945, "radius": 128.72, "rating": 3.48, "flooring_experience": "{carpet,cement,tiles,wood}"},
+  {"id": 10, "name": "Broken", "lat": 95, "lng": 27.3166945, "radius": 128.72, "rating": 11, "flooring_experience": ["carpet", "marble"]},
+  {"id": 11, "name": "Unparsable", "lat": "north", "lng": 27.3166945, "radius": 128.72, "rating": 1, "flooring_experience": ["carpet"]}
+]`
+
+const partnersNDJSON = `{"id": 1, "name": "Lazz", "lat": 40.076762, "lng": 113.300129, "radius": 108.83, "rating": 0.96, "flooring_experience": ["carpet", "tiles"]}
+{"id": 9, "name": "Latz", "lat": 50.8105808, "lng": 27.3166945, "radius": 128.72, "rating": 3.48, "flooring_experience": "{carpet,cement,tiles,wood}"}
+
+{"id": 10, "name": "Broken", "lat": 95, "lng": 27.3166945, "radius": 128.72, "rating": 11, "flooring_experience": ["carpet", "marble"]}
+{"id": 11, "name": "Unparsable", "lat": "north"
+`
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		description string
+		format      importer.Format
+		input       string
+	}{
+		{description: "CSV with Python-style material lists", format: importer.CSV, input: partnersCSV},
+		{description: "JSON array", format: importer.JSON, input: partnersJSON},
+		{description: "NDJSON", format: importer.NDJSON, input: partnersNDJSON},
+	}
+
+	for _, test := range tests {
+		rows, err := importer.Read(strings.NewReader(test.input), test.format)
+		assert.NoErrorf(t, err, test.description)
+		assert.Lenf(t, rows, 4, test.description)
+
+		partners := repository.NewMemory()
+		report, err := importer.Import(context.Background(), partners, taxonomy.NewCatalog(taxonomy.Defaults()), rows, importer.Options{BatchSize: 1})
+		assert.NoErrorf(t, err, test.description)
+		assert.Equalf(t, 2, report.Valid, test.description)
+		assert.Equalf(t, 2, report.Imported, test.description)
+		if assert.Lenf(t, report.Failed, 2, test.description) {
+			assert.Equalf(t, 3, report.Failed[0].Row, test.description)
+			assert.Lenf(t, report.Failed[0].Errors, 3, "%s: lat, rating and material are invalid", test.description)
+			assert.Equalf(t, 4, report.Failed[1].Row, test.description)
+		}
+
+		latz, err := partners.Get(context.Background(), 9)
+		assert.NoErrorf(t, err, test.description)
+		assert.Equalf(t, []string{"carpet", "cement", "tiles", "wood"}, latz.Materials(), test.description)
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	rows, err := importer.Read(strings.NewReader(partnersCSV), importer.CSV)
+	assert.NoError(t, err)
+
+	partners := repository.NewMemory()
+	report, err := importer.Import(context.Background(), partners, taxonomy.NewCatalog(taxonomy.Defaults()), rows, importer.Options{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 0, report.Imported)
+
+	all, err := partners.List(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+// failingBatch fails the batch holding the partner with id failing like the
+// transaction of a database, without writing any batch.
+type failingBatch struct {
+	*repository.Memory
+	failing int16
+	batches int
+}
+
+func (r *failingBatch) UpsertBatches(ctx context.Context, batchSize int, partners ...*models.Partner) error {
+	for start := 0; start < len(partners); start += batchSize {
+		r.batches++
+		end := start + batchSize
+		if end > len(partners) {
+			end = len(partners)
+		}
+		for _, p := range partners[start:end] {
+			if p.Id == r.failing {
+				return errors.New("value too long for type character varying(255)")
+			}
+		}
+	}
+	return r.Memory.Upsert(ctx, partners...)
+}
+
+func TestImportFailingBatch(t *testing.T) {
+	rows, err := importer.Read(strings.NewReader(partnersCSV), importer.CSV)
+	assert.NoError(t, err)
+
+	// the second batch fails, the first one is not kept either
+	partners := &failingBatch{Memory: repository.NewMemory(), failing: 9}
+	report, err := importer.Import(context.Background(), partners, taxonomy.NewCatalog(taxonomy.Defaults()), rows, importer.Options{BatchSize: 1})
+	assert.Error(t, err)
+	assert.Equal(t, 2, partners.batches)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 0, report.Imported)
+
+	all, err := partners.List(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, all)
+}
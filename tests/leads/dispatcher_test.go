@@ -0,0 +1,158 @@
+package leads
+
+import (
+	"aroundHome/app/leads"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func testDispatcher(t *testing.T, offers, accepts int, partnerIds ...int16) (*leads.Dispatcher, *clock, int64) {
+	requests := repository.NewMemoryRequests()
+	request := &models.CustomerRequest{Materials: []string{"carpet"}, Phone: "0160153700132"}
+	for _, id := range partnerIds {
+		request.Matches = append(request.Matches, &models.PartnerWithDistance{Partner: models.Partner{Id: id}})
+	}
+	require.NoError(t, requests.Create(context.Background(), request))
+	c := &clock{now: time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)}
+	return &leads.Dispatcher{
+		Leads:    repository.NewMemoryLeads(),
+		Requests: requests,
+		Offers:   offers,
+		Accepts:  accepts,
+		TTL:      time.Hour,
+		Now:      c.Now,
+	}, c, request.Id
+}
+
+func states(recs []*models.Lead) map[int16]models.LeadState {
+	m := make(map[int16]models.LeadState, len(recs))
+	for _, rec := range recs {
+		m[rec.PartnerId] = rec.State
+	}
+	return m
+}
+
+func TestLeadStateTransitions(t *testing.T) {
+	assert.True(t, models.LeadOffered.CanTransition(models.LeadViewed))
+	assert.True(t, models.LeadOffered.CanTransition(models.LeadAccepted))
+	assert.True(t, models.LeadViewed.CanTransition(models.LeadDeclined))
+	assert.False(t, models.LeadViewed.CanTransition(models.LeadOffered))
+	for _, final := range []models.LeadState{models.LeadAccepted, models.LeadDeclined, models.LeadExpired} {
+		for _, to := range []models.LeadState{models.LeadOffered, models.LeadViewed, models.LeadAccepted, models.LeadDeclined, models.LeadExpired} {
+			assert.Falsef(t, final.CanTransition(to), "%s to %s", final, to)
+		}
+	}
+}
+
+func TestDispatchOffersTopPartners(t *testing.T) {
+	ctx := context.Background()
+	d, _, requestId := testDispatcher(t, 2, 1, 10, 20, 30)
+
+	recs, err := d.Dispatch(ctx, requestId)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]models.LeadState{10: models.LeadOffered, 20: models.LeadOffered}, states(recs))
+
+	// dispatching again does not offer more
+	recs, err = d.Dispatch(ctx, requestId)
+	require.NoError(t, err)
+	assert.Len(t, recs, 2)
+
+	_, err = d.Dispatch(ctx, requestId+1)
+	assert.Equal(t, repository.ErrRequestNotFound, err)
+}
+
+func TestDeclineOffersNextPartner(t *testing.T) {
+	ctx := context.Background()
+	d, _, requestId := testDispatcher(t, 1, 1, 10, 20)
+	recs, err := d.Dispatch(ctx, requestId)
+	require.NoError(t, err)
+
+	lead, err := d.Decline(ctx, recs[0].Id)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadDeclined, lead.State)
+
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]models.LeadState{10: models.LeadDeclined, 20: models.LeadOffered}, states(recs))
+
+	// no partners left to offer
+	_, err = d.Decline(ctx, recs[1].Id)
+	require.NoError(t, err)
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Len(t, recs, 2)
+}
+
+func TestAcceptStopsOffering(t *testing.T) {
+	ctx := context.Background()
+	d, _, requestId := testDispatcher(t, 2, 1, 10, 20, 30)
+	recs, err := d.Dispatch(ctx, requestId)
+	require.NoError(t, err)
+
+	_, err = d.View(ctx, recs[1].Id)
+	require.NoError(t, err)
+	_, err = d.Accept(ctx, recs[1].Id)
+	require.NoError(t, err)
+
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]models.LeadState{10: models.LeadExpired, 20: models.LeadAccepted}, states(recs))
+
+	_, err = d.Accept(ctx, recs[0].Id)
+	var transitionErr *leads.TransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, models.LeadExpired, transitionErr.From)
+	assert.Equal(t, models.LeadAccepted, transitionErr.To)
+}
+
+func TestAcceptsSeveralPartners(t *testing.T) {
+	ctx := context.Background()
+	d, _, requestId := testDispatcher(t, 1, 2, 10, 20, 30)
+	recs, err := d.Dispatch(ctx, requestId)
+	require.NoError(t, err)
+
+	_, err = d.Accept(ctx, recs[0].Id)
+	require.NoError(t, err)
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]models.LeadState{10: models.LeadAccepted, 20: models.LeadOffered}, states(recs))
+
+	_, err = d.Accept(ctx, recs[1].Id)
+	require.NoError(t, err)
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Len(t, recs, 2, "partner 30 is not offered the request")
+}
+
+func TestExpire(t *testing.T) {
+	ctx := context.Background()
+	d, c, requestId := testDispatcher(t, 1, 1, 10, 20)
+	recs, err := d.Dispatch(ctx, requestId)
+	require.NoError(t, err)
+
+	c.now = c.now.Add(30 * time.Minute)
+	require.NoError(t, d.Expire(ctx))
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]models.LeadState{10: models.LeadOffered}, states(recs))
+
+	c.now = c.now.Add(time.Hour)
+	_, err = d.Accept(ctx, recs[0].Id)
+	assert.Error(t, err, "overdue leads cannot be accepted")
+
+	require.NoError(t, d.Expire(ctx))
+	recs, err = d.Leads.ForRequest(ctx, requestId)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]models.LeadState{10: models.LeadExpired, 20: models.LeadOffered}, states(recs))
+	assert.Equal(t, c.now.Add(time.Hour), recs[1].ExpiresAt)
+}
@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"aroundHome/app/dto"
+	"aroundHome/app/logging"
+	"aroundHome/app/problem"
+	"aroundHome/tests/fixtures"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lines decodes the JSON lines written to buf.
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var decoded []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal([]byte(line), &fields), line)
+		decoded = append(decoded, fields)
+	}
+	return decoded
+}
+
+func TestLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := logging.New(buf, logging.LevelWarn)
+	ctx := logging.WithRequestId(context.Background(), "abc")
+
+	logger.Log(ctx, logging.LevelInfo, "dropped")
+	logger.Log(ctx, logging.LevelWarn, "kept", "error", errors.New("boom"), "rows", 3, "dangling")
+	logger.Log(context.Background(), logging.LevelError, "outside of a request")
+
+	decoded := lines(t, buf)
+	require.Len(t, decoded, 2)
+	assert.Equal(t, "warn", decoded[0]["level"])
+	assert.Equal(t, "kept", decoded[0]["msg"])
+	assert.Equal(t, "abc", decoded[0]["request_id"])
+	assert.Equal(t, "boom", decoded[0]["error"])
+	assert.Equal(t, float64(3), decoded[0]["rows"])
+	assert.Equal(t, "!MISSING", decoded[0]["dangling"])
+	assert.NotEmpty(t, decoded[0]["time"])
+	assert.NotContains(t, decoded[1], "request_id")
+	// the fields follow the time, level and message in the order given
+	assert.True(t, strings.HasPrefix(buf.String(), `{"time":`))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logging.ParseLevel("DEBUG")
+	require.NoError(t, err)
+	assert.Equal(t, logging.LevelDebug, level)
+	level, err = logging.ParseLevel("error")
+	require.NoError(t, err)
+	assert.Equal(t, logging.LevelError, level)
+	_, err = logging.ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+// testApp returns an app with the logging middleware of serve, logging to the returned buffer.
+func testApp(t *testing.T) (*fiber.App, *bytes.Buffer) {
+	buf := new(bytes.Buffer)
+	previous := logging.Default()
+	logging.SetDefault(logging.New(buf, logging.LevelInfo))
+	t.Cleanup(func() { logging.SetDefault(previous) })
+
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...), logging.Middleware())
+	webApp.Get("/unavailable", func(*fiber.Ctx) error { return errors.New("database is down") })
+	return webApp, buf
+}
+
+func TestMiddlewareKeepsRequestId(t *testing.T) {
+	webApp, buf := testApp(t)
+
+	req := httptest.NewRequest("GET", "/v1/query?address=40.076762,113.300129&material=carpet,tiles&phone=0160", nil)
+	req.Header.Set("X-Request-ID", "checkout-42")
+	resp, err := webApp.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "checkout-42", resp.Header.Get("X-Request-ID"))
+	result := new(dto.MatchResult)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(result))
+	require.NotEmpty(t, result.Matches)
+
+	decoded := lines(t, buf)
+	require.Len(t, decoded, 1)
+	access := decoded[0]
+	assert.Equal(t, "info", access["level"])
+	assert.Equal(t, "request", access["msg"])
+	assert.Equal(t, "checkout-42", access["request_id"])
+	assert.Equal(t, "GET", access["method"])
+	// the query string may hold personal data like the phone number
+	assert.Equal(t, "/v1/query", access["path"])
+	assert.Equal(t, "/v1/query", access["route"])
+	assert.Equal(t, float64(200), access["status"])
+	assert.Equal(t, float64(len(result.Matches)), access["results"])
+	assert.Contains(t, access, "latency_ms")
+}
+
+func TestMiddlewareLogsPartnerCount(t *testing.T) {
+	webApp, buf := testApp(t)
+
+	resp, err := webApp.Test(httptest.NewRequest("GET", "/v1/partners?limit=2", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	page := new(dto.PartnerPage)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(page))
+	require.Len(t, page.Partners, 2)
+
+	decoded := lines(t, buf)
+	require.Len(t, decoded, 1)
+	assert.Equal(t, "/v1/partners", decoded[0]["route"])
+	assert.Equal(t, float64(2), decoded[0]["results"])
+}
+
+func TestMiddlewareGeneratesRequestId(t *testing.T) {
+	webApp, buf := testApp(t)
+
+	for _, header := range []string{"", "has spaces", strings.Repeat("x", 129)} {
+		buf.Reset()
+		req := httptest.NewRequest("GET", "/v1/partners/2", nil)
+		if header != "" {
+			req.Header.Set("X-Request-ID", header)
+		}
+		resp, err := webApp.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+		id := resp.Header.Get("X-Request-ID")
+		assert.Len(t, id, 32)
+
+		p := new(problem.Problem)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(p))
+		assert.Equal(t, id, p.RequestId)
+		decoded := lines(t, buf)
+		require.Len(t, decoded, 1)
+		assert.Equal(t, id, decoded[0]["request_id"])
+		assert.Equal(t, "/v1/partners/:id", decoded[0]["route"])
+		assert.Equal(t, float64(404), decoded[0]["status"])
+	}
+}
+
+func TestMiddlewareLogsErrors(t *testing.T) {
+	webApp, buf := testApp(t)
+
+	resp, err := webApp.Test(httptest.NewRequest("GET", "/unavailable", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	_, err = webApp.Test(httptest.NewRequest("GET", "/not-found", nil), -1)
+	require.NoError(t, err)
+	resp, err = webApp.Test(httptest.NewRequest("GET", "/v1/partners/abc", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	decoded := lines(t, buf)
+	require.Len(t, decoded, 4)
+	assert.Equal(t, "error", decoded[0]["level"])
+	assert.Equal(t, "database is down", decoded[0]["error"])
+	assert.Equal(t, decoded[0]["request_id"], decoded[1]["request_id"])
+	assert.Equal(t, float64(500), decoded[1]["status"])
+	assert.Equal(t, float64(404), decoded[2]["status"])
+	assert.NotContains(t, decoded[2], "route")
+	// rejected by the validation against the OpenAPI description
+	assert.Equal(t, float64(400), decoded[3]["status"])
+	assert.Equal(t, "/v1/partners/:id", decoded[3]["route"])
+}
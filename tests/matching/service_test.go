@@ -0,0 +1,87 @@
+package matching
+
+import (
+	"aroundHome/app/matching"
+	"aroundHome/app/models"
+	"aroundHome/app/ranking"
+	"aroundHome/app/repository"
+	"aroundHome/app/taxonomy"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPartners holds every Match until released and records how many ran at the same time.
+type countingPartners struct {
+	repository.PartnerRepository
+	release chan struct{}
+
+	mu      sync.Mutex
+	running int
+	max     int
+}
+
+func (p *countingPartners) Match(ctx context.Context, query repository.MatchQuery) ([]*models.PartnerWithDistance, error) {
+	p.mu.Lock()
+	p.running++
+	if p.running > p.max {
+		p.max = p.running
+	}
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+	<-p.release
+	return p.PartnerRepository.Match(ctx, query)
+}
+
+func (p *countingPartners) counts() (running, max int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running, p.max
+}
+
+func TestMatchBatchBoundsConcurrency(t *testing.T) {
+	partners := &countingPartners{
+		PartnerRepository: repository.NewMemory(
+			&models.Partner{Id: 1, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"},
+		),
+		release: make(chan struct{}),
+	}
+	service := &matching.Service{
+		Partners:  partners,
+		Materials: taxonomy.NewStore(repository.NewMemoryMaterials(taxonomy.Defaults()...), taxonomy.DefaultTTL),
+		Ranker:    ranking.Default{},
+	}
+	requests := make([]matching.Request, 20)
+	for i := range requests {
+		requests[i] = matching.Request{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet"}}
+	}
+
+	done := make(chan []matching.BatchResult)
+	go func() { done <- service.MatchBatch(context.Background(), requests, 3) }()
+
+	// the batch fills the bound and waits there while every match is held
+	assert.Eventually(t, func() bool {
+		running, _ := partners.counts()
+		return running == 3
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	running, _ := partners.counts()
+	assert.Equal(t, 3, running)
+
+	close(partners.release)
+	results := <-done
+	_, max := partners.counts()
+	assert.Equal(t, 3, max)
+	assert.Len(t, results, 20)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		assert.Len(t, result.Matches, 1)
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"aroundHome/app/metrics"
+	"aroundHome/tests/fixtures"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testApp returns an app with the metrics middleware of serve.
+func testApp() *fiber.App {
+	return fixtures.App(fixtures.Services(fixtures.Partners()...), metrics.Middleware())
+}
+
+func TestMetrics(t *testing.T) {
+	webApp := testApp()
+	for _, route := range []string{
+		"/v1/partners/1",
+		"/v1/partners/2",
+		"/v1/partners/abc",
+		"/not-found",
+		"/v1/query?address=40.076762,113.300129&material=carpet,tiles",
+		"/v1/query?address=0,0&material=wood",
+		"/query/?address=40.076762,113.300129&material=wood",
+	} {
+		_, err := webApp.Test(httptest.NewRequest("GET", route, nil), -1)
+		require.NoError(t, err)
+	}
+
+	resp, err := webApp.Test(httptest.NewRequest("GET", "/metrics", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/v1/partners/:id",status="200"} 1`,
+		`http_requests_total{method="GET",route="/v1/partners/:id",status="404"} 1`,
+		`http_requests_total{method="GET",route="/v1/partners/:id",status="400"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_requests_total{method="GET",route="/query",status="200"} 1`,
+		`http_requests_total{method="GET",route="/v1/query",status="200"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="/v1/query"} 2`,
+		`aroundhome_match_partners_count 3`,
+		`aroundhome_match_partners_bucket{le="0"} 1`,
+		`aroundhome_match_empty_total 1`,
+		`aroundhome_match_materials_total{material="carpet"} 1`,
+		`aroundhome_match_materials_total{material="wood"} 2`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, text, line)
+	}
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"aroundHome/db/migrations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	loaded, err := migrations.Load()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+
+	for i, m := range loaded {
+		// versions are numbered without gaps starting at 1
+		assert.Equalf(t, int64(i+1), m.Version, "version of %s", m.Name)
+		assert.NotEmptyf(t, m.Up, "up migration of %d_%s", m.Version, m.Name)
+		assert.NotEmptyf(t, m.Down, "down migration of %d_%s", m.Version, m.Name)
+	}
+}
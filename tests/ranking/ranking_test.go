@@ -0,0 +1,66 @@
+package ranking
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/ranking"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMatches() []*models.PartnerWithDistance {
+	return []*models.PartnerWithDistance{
+		{Partner: models.Partner{Id: 1, Rating: 9.9, Radius: 150, FlooringExperience: "{carpet,tiles}"}, Distance: 120},
+		{Partner: models.Partner{Id: 2, Rating: 9.8, Radius: 150, FlooringExperience: "{carpet,tiles}"}, Distance: 1},
+		{Partner: models.Partner{Id: 3, Rating: 9.8, Radius: 150, FlooringExperience: "{carpet,tiles}", MaxSqm: 20}, Distance: 1},
+		{Partner: models.Partner{Id: 4, Rating: 5, Radius: 150, FlooringExperience: "{carpet,tiles}"}, Distance: 0},
+	}
+}
+
+func ids(matches []*models.PartnerWithDistance) []int16 {
+	ids := make([]int16, len(matches))
+	for i, m := range matches {
+		ids[i] = m.Partner.Id
+	}
+	return ids
+}
+
+func TestDefaultKeepsRatingOrder(t *testing.T) {
+	matches := testMatches()
+	ranking.Default{}.Rank(ranking.Request{Materials: []string{"carpet"}, Sqm: 65}, matches)
+	assert.Equal(t, []int16{1, 2, 3, 4}, ids(matches))
+	assert.Equal(t, float32(9.9), matches[0].Score)
+}
+
+func TestWeightedPrefersCloseFittingPartners(t *testing.T) {
+	matches := testMatches()
+	ranking.Weighted{Weights: ranking.DefaultWeights}.Rank(ranking.Request{Materials: []string{"carpet"}, Sqm: 65}, matches)
+	assert.Equal(t, []int16{2, 3, 1, 4}, ids(matches))
+
+	for _, m := range matches {
+		var sum float32
+		for _, v := range m.Factors {
+			sum += v
+		}
+		assert.InDeltaf(t, m.Score, sum, 1e-6, "factors of partner %d add up to the score", m.Partner.Id)
+	}
+	assert.Less(t, matches[1].Factors["size"], matches[0].Factors["size"])
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("RANKING", "weighted")
+	t.Setenv("RANKING_WEIGHT_DISTANCE", "0.5")
+	ranker, err := ranking.FromEnv()
+	assert.NoError(t, err)
+	expected := ranking.DefaultWeights
+	expected.Distance = 0.5
+	assert.Equal(t, ranking.Weighted{Weights: expected}, ranker)
+
+	t.Setenv("RANKING_WEIGHT_SIZE", "large")
+	_, err = ranking.FromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("RANKING", "random")
+	_, err = ranking.FromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"aroundHome/app"
+	"aroundHome/app/geo"
+	"aroundHome/app/logging"
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/db/migrations"
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testDatabase connects to the database named by PG_TEST_DATABASE and migrates it.
+// Tests using it are skipped when the variable is not set.
+func testDatabase(t *testing.T) *sql.DB {
+	name := os.Getenv("PG_TEST_DATABASE")
+	if name == "" {
+		t.Skip("PG_TEST_DATABASE is not set")
+	}
+	t.Setenv("PG_DATABASE", name)
+	dsn, err := app.DataSourceName()
+	require.NoError(t, err)
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func explain(t *testing.T, conn *sql.Conn, query string, args ...interface{}) string {
+	rows, err := conn.QueryContext(context.Background(), "explain "+query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+	plan := make([]string, 0)
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		plan = append(plan, line)
+	}
+	require.NoError(t, rows.Err())
+	return strings.Join(plan, "\n")
+}
+
+func TestPostgresMatchUsesIndexes(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+	partners := repository.NewPostgres(db)
+	require.NoError(t, partners.Upsert(ctx,
+		&models.Partner{Id: 1, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"},
+		&models.Partner{Id: 883, Name: "Meevee", Lat: 39.296173, Lng: 113.690698, Radius: 127.98, Rating: 5.25, FlooringExperience: "{carpet,tiles,wood}"},
+	))
+
+	// partners covering the customer without the material and partners with the
+	// material far away, so each index alone leaves many rows to filter
+	filler := make([]*models.Partner, 0, 400)
+	for i := 0; i < 200; i++ {
+		filler = append(filler,
+			&models.Partner{Id: int16(2000 + i), Name: "Near", Lat: 40.0, Lng: 113.3, Radius: 150, Rating: 1, FlooringExperience: "{wood}"},
+			&models.Partner{Id: int16(3000 + i), Name: "Far", Lat: 52.5, Lng: 13.4, Radius: 10, Rating: 1, FlooringExperience: "{carpet}"},
+		)
+	}
+	require.NoError(t, partners.Upsert(ctx, filler...))
+	t.Cleanup(func() {
+		for _, p := range filler {
+			_ = partners.Delete(ctx, p.Id)
+		}
+	})
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "analyze partners")
+	require.NoError(t, err)
+	// the test table is still too small for the planner to prefer indexes on its own
+	_, err = conn.ExecContext(ctx, "set enable_seqscan = off")
+	require.NoError(t, err)
+
+	plan := explain(t, conn, repository.MatchSql(), 40.076762, 113.300129, pq.Array([]string{"carpet"}))
+	assert.Contains(t, plan, "partners_coverage_idx")
+	assert.Contains(t, plan, "partners_flooring_experience_idx")
+
+	// matching a partner at exactly the customer location must not produce NaN
+	recs, err := partners.Match(ctx, repository.MatchQuery{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet", "tiles"}})
+	require.NoError(t, err)
+	ids := make([]int16, 0)
+	for _, rec := range recs {
+		ids = append(ids, rec.Partner.Id)
+		if rec.Partner.Id == 1 {
+			assert.InDelta(t, 0, rec.Distance, 0.001)
+		}
+	}
+	assert.Subset(t, ids, []int16{883, 1})
+
+	// a closed statement is prepared again by the next match
+	require.NoError(t, partners.Close())
+	again, err := partners.Match(ctx, repository.MatchQuery{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet", "tiles"}})
+	require.NoError(t, err)
+	assert.Len(t, again, len(recs))
+	require.NoError(t, partners.Close())
+}
+
+func TestPostgresFindPagesLikeMemory(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+	partners := []*models.Partner{
+		{Id: 1, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"},
+		{Id: 883, Name: "Meevee", Lat: 39.296173, Lng: 113.690698, Radius: 127.98, Rating: 5.25, FlooringExperience: "{carpet,tiles,wood}"},
+		{Id: 805, Name: "Blogtags", Lat: 49.6087627, Lng: 18.4861804, Radius: 100, Rating: 9.99, FlooringExperience: "{carpet,tiles,wood}"},
+		{Id: 806, Name: "blog_post", Lat: 49.6, Lng: 18.5, Radius: 100, Rating: 5.25, FlooringExperience: "{cement}"},
+	}
+	postgres := repository.NewPostgres(db)
+	require.NoError(t, postgres.Upsert(ctx, partners...))
+	memory := repository.NewMemory(partners...)
+
+	minRating := float32(0.96)
+	filters := []repository.PartnerFilter{
+		{Sort: repository.SortId},
+		{Sort: repository.SortName, NamePrefix: "blog"},
+		{Sort: repository.SortName, NamePrefix: "blog_"},
+		{Sort: repository.SortRating, MinRating: &minRating},
+		{Sort: repository.SortRatingDesc, AnyMaterials: []string{"wood", "cement"}},
+		{Sort: repository.SortId, Materials: []string{"carpet", "wood"}, Ids: []int16{805, 883, 1}},
+		{Sort: repository.SortId, Box: &geo.Box{MinLat: 35, MinLng: 170, MaxLat: 45, MaxLng: 115}},
+	}
+	pages := func(r repository.PartnerRepository, filter repository.PartnerFilter) []int16 {
+		ids := make([]int16, 0)
+		filter.Limit = 1
+		if filter.Ids == nil {
+			// ignore partners other tests left in the database
+			filter.Ids = []int16{1, 883, 805, 806}
+		}
+		for {
+			page, err := r.Find(ctx, filter)
+			require.NoError(t, err)
+			for _, p := range page.Partners {
+				ids = append(ids, p.Id)
+			}
+			if page.Next == nil {
+				return ids
+			}
+			filter.After = page.Next
+		}
+	}
+	for _, filter := range filters {
+		expected := pages(memory, filter)
+		assert.NotEmptyf(t, expected, "%+v", filter)
+		assert.Equalf(t, expected, pages(postgres, filter), "%+v", filter)
+	}
+}
+
+func TestPostgresUpsertBatches(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+	partners := repository.NewPostgres(db)
+	t.Cleanup(func() {
+		for _, id := range []int16{4001, 4002, 4003} {
+			partners.Delete(ctx, id)
+		}
+	})
+
+	// the third batch fails, the first two are rolled back with it
+	failing := []*models.Partner{
+		{Id: 4001, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"},
+		{Id: 4002, Name: "Meevee", Lat: 39.296173, Lng: 113.690698, Radius: 127.98, Rating: 5.25, FlooringExperience: "{wood}"},
+		{Id: 4003, Name: strings.Repeat("x", 256), Lat: 49.6087627, Lng: 18.4861804, Radius: 100, Rating: 9.99, FlooringExperience: "{wood}"},
+	}
+	assert.Error(t, partners.UpsertBatches(ctx, 1, failing...))
+	for _, p := range failing {
+		_, err := partners.Get(ctx, p.Id)
+		assert.ErrorIsf(t, err, repository.ErrNotFound, "partner %d", p.Id)
+	}
+
+	// a partner repeated in a batch is written as last given
+	failing[2].Name = "Blogtags"
+	renamed := *failing[0]
+	renamed.Name = "Lazz 2"
+	require.NoError(t, partners.UpsertBatches(ctx, 2, append(failing, &renamed)...))
+	rec, err := partners.Get(ctx, 4001)
+	require.NoError(t, err)
+	assert.Equal(t, "Lazz 2", rec.Name)
+	assert.Equal(t, []string{"carpet", "tiles"}, rec.Materials())
+	rec, err = partners.Get(ctx, 4003)
+	require.NoError(t, err)
+	assert.Equal(t, "Blogtags", rec.Name)
+}
+
+func TestPostgresTracesAndLogsStatements(t *testing.T) {
+	db := testDatabase(t)
+	ctx := logging.WithRequestId(context.Background(), "abc")
+	buf := new(bytes.Buffer)
+	previousLogger := logging.Default()
+	logging.SetDefault(logging.New(buf, logging.LevelDebug))
+	t.Cleanup(func() { logging.SetDefault(previousLogger) })
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	partners := repository.NewPostgres(db)
+	require.NoError(t, partners.Upsert(ctx,
+		&models.Partner{Id: 1, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"},
+	))
+	recs, err := partners.Match(ctx, repository.MatchQuery{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet"}})
+	require.NoError(t, err)
+	_, err = partners.Get(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, partners.Delete(ctx, 1))
+	assert.ErrorIs(t, partners.Delete(ctx, 1), repository.ErrNotFound)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	assert.Equal(t, []string{"partners.upsert", "partners.match", "partners.get", "partners.delete", "partners.delete"}, names)
+	// a missing partner is no failure of the statement
+	assert.Equal(t, codes.Unset, spans[4].Status().Code)
+	for _, kv := range spans[1].Attributes() {
+		if kv.Key == "db.rows" {
+			assert.Equal(t, int64(len(recs)), kv.Value.AsInt64())
+		}
+	}
+	// every statement is logged for the request
+	logged := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, logged, 5)
+	assert.Contains(t, logged[0], `"request_id":"abc","statement":"partners.upsert","rows":1`)
+	assert.Contains(t, logged[1], `"request_id":"abc","statement":"partners.match"`)
+	assert.Contains(t, logged[2], `"request_id":"abc","statement":"partners.get","rows":1`)
+	assert.Contains(t, logged[4], `"request_id":"abc","statement":"partners.delete","rows":0`)
+}
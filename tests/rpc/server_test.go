@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"aroundHome/app/rpc"
+	"aroundHome/app/rpc/pb"
+	"aroundHome/tests/fixtures"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testConn serves the partners over an in-memory listener and returns a client connection to it.
+func testConn(t *testing.T) *grpc.ClientConn {
+	server := rpc.NewServer(fixtures.Services(fixtures.Partners()...))
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestMatchPartners(t *testing.T) {
+	client := pb.NewPartnersClient(testConn(t))
+
+	res, err := client.MatchPartners(context.Background(), &pb.MatchPartnersRequest{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet", "tiles"}, Sqm: 35})
+	require.NoError(t, err)
+	ids := make([]int32, 0)
+	for _, m := range res.Matches {
+		ids = append(ids, m.Partner.Id)
+	}
+	assert.Equal(t, []int32{883, 1}, ids)
+	assert.Greater(t, res.Matches[0].DistanceKm, float64(0))
+	assert.Equal(t, 40.076763, res.Matches[1].Partner.Lat)
+
+	_, err = client.MatchPartners(context.Background(), &pb.MatchPartnersRequest{Lat: 140, Materials: []string{"vinyl"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetPartner(t *testing.T) {
+	client := pb.NewPartnersClient(testConn(t))
+
+	partner, err := client.GetPartner(context.Background(), &pb.GetPartnerRequest{Id: 883})
+	require.NoError(t, err)
+	assert.Equal(t, "Meevee", partner.Name)
+	assert.Equal(t, []string{"carpet", "tiles", "wood"}, partner.Materials)
+
+	_, err = client.GetPartner(context.Background(), &pb.GetPartnerRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetPartner(context.Background(), &pb.GetPartnerRequest{Id: 1 << 20})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListPartners(t *testing.T) {
+	client := pb.NewPartnersClient(testConn(t))
+
+	minRating := 5.0
+	stream, err := client.ListPartners(context.Background(), &pb.ListPartnersRequest{Materials: []string{"wood"}, MinRating: &minRating, Sort: "-rating"})
+	require.NoError(t, err)
+	ids := make([]int32, 0)
+	for {
+		partner, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, partner.Id)
+	}
+	assert.Equal(t, []int32{805, 883}, ids)
+
+	stream, err = client.ListPartners(context.Background(), &pb.ListPartnersRequest{Sort: "distance"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHealth(t *testing.T) {
+	client := healthpb.NewHealthClient(testConn(t))
+
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "aroundhome.v1.Partners"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+}
@@ -0,0 +1,128 @@
+package spatial
+
+import (
+	"aroundHome/app/models"
+	"aroundHome/app/repository"
+	"aroundHome/app/spatial"
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var materialSets = []string{"{carpet}", "{carpet,tiles}", "{carpet,tiles,wood}", "{carpet,cement,tiles,wood}"}
+
+func randomPartners(r *rand.Rand, n int) []*models.Partner {
+	partners := make([]*models.Partner, n)
+	for i := range partners {
+		partners[i] = &models.Partner{
+			Id:                 int16(i + 1),
+			Name:               "partner",
+			Lat:                float32(r.Float64()*180 - 90),
+			Lng:                float32(r.Float64()*360 - 180),
+			Radius:             float32(r.Float64() * 500),
+			Rating:             float32(r.Intn(100)) / 10,
+			FlooringExperience: materialSets[r.Intn(len(materialSets))],
+		}
+	}
+	return partners
+}
+
+// TestGridMatchesFullScan compares the grid with the full scan of repository.Memory,
+// including points close to the poles and the antimeridian.
+func TestGridMatchesFullScan(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	partners := randomPartners(r, 2000)
+	memory := repository.NewMemory(partners...)
+	grid := spatial.NewGrid(partners, spatial.DefaultCellSize)
+	assert.Equal(t, len(partners), grid.Len())
+
+	queries := []repository.MatchQuery{
+		{Lat: 89.9, Lng: 10, Materials: []string{"carpet"}},
+		{Lat: -89.9, Lng: -170, Materials: []string{"carpet"}},
+		{Lat: 10, Lng: 179.99, Materials: []string{"carpet"}},
+		{Lat: -10, Lng: -180, Materials: []string{"carpet"}},
+	}
+	for i := 0; i < 200; i++ {
+		queries = append(queries, repository.MatchQuery{
+			Lat:       r.Float64()*180 - 90,
+			Lng:       r.Float64()*360 - 180,
+			Materials: []string{"carpet", "tiles"},
+		})
+	}
+
+	for _, query := range queries {
+		expected, err := memory.Match(context.Background(), query)
+		assert.NoError(t, err)
+		actual := grid.Match(query)
+		assert.Equalf(t, expected, actual, "query %+v", query)
+	}
+}
+
+func TestIndexedRefreshesAfterUpsert(t *testing.T) {
+	ctx := context.Background()
+	indexed, err := spatial.NewIndexed(ctx, repository.NewMemory(), spatial.DefaultCellSize)
+	assert.NoError(t, err)
+
+	query := repository.MatchQuery{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet"}}
+	recs, err := indexed.Match(ctx, query)
+	assert.NoError(t, err)
+	assert.Empty(t, recs)
+
+	err = indexed.Upsert(ctx, &models.Partner{Id: 1, Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"})
+	assert.NoError(t, err)
+	recs, err = indexed.Match(ctx, query)
+	assert.NoError(t, err)
+	if assert.Len(t, recs, 1) {
+		assert.Equal(t, int16(1), recs[0].Partner.Id)
+	}
+}
+
+// failingList fails to list partners once failing is set, writes still succeed.
+type failingList struct {
+	*repository.Memory
+	failing bool
+}
+
+func (p *failingList) List(ctx context.Context) ([]*models.Partner, error) {
+	if p.failing {
+		return nil, errors.New("connection refused")
+	}
+	return p.Memory.List(ctx)
+}
+
+func TestIndexedKeepsChangesWhenRefreshFails(t *testing.T) {
+	ctx := context.Background()
+	partners := &failingList{Memory: repository.NewMemory()}
+	indexed, err := spatial.NewIndexed(ctx, partners, spatial.DefaultCellSize)
+	require.NoError(t, err)
+	refreshed := indexed.Refreshed()
+
+	// the writes are committed, so they succeed although the index is not rebuilt
+	partners.failing = true
+	partner := &models.Partner{Name: "Lazz", Lat: 40.076762, Lng: 113.300129, Radius: 108.83, Rating: 0.96, FlooringExperience: "{carpet,tiles}"}
+	assert.NoError(t, indexed.Create(ctx, partner))
+	partner.Rating = 0.5
+	assert.NoError(t, indexed.Update(ctx, partner))
+	assert.NoError(t, indexed.Upsert(ctx, &models.Partner{Id: 2, Name: "Flooro", Lat: 52.52, Lng: 13.405, Radius: 30, Rating: 7.5, FlooringExperience: "{wood}"}))
+	assert.Equal(t, refreshed, indexed.Refreshed())
+	rec, err := partners.Get(ctx, partner.Id)
+	require.NoError(t, err)
+	assert.Equal(t, float32(0.5), rec.Rating)
+
+	// the next refresh picks the changes up
+	partners.failing = false
+	require.NoError(t, indexed.Refresh(ctx))
+	query := repository.MatchQuery{Lat: 40.076762, Lng: 113.300129, Materials: []string{"carpet"}}
+	recs, err := indexed.Match(ctx, query)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 1)
+
+	partners.failing = true
+	assert.NoError(t, indexed.Delete(ctx, partner.Id))
+	_, err = partners.Get(ctx, partner.Id)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"aroundHome/app/tracing"
+	"aroundHome/tests/fixtures"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider recording the ended spans until the test ends.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	_, err := tracing.Setup(context.Background(), tracing.None)
+	require.NoError(t, err)
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// testApp returns an app with the tracing middleware of serve.
+func testApp() *fiber.App {
+	webApp := fixtures.App(fixtures.Services(fixtures.Partners()...), tracing.Middleware())
+	webApp.Get("/unavailable", func(*fiber.Ctx) error { return fiber.ErrServiceUnavailable })
+	return webApp
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestServerSpan(t *testing.T) {
+	recorder := recordSpans(t)
+	webApp := testApp()
+
+	req := httptest.NewRequest("GET", "/v1/partners/1?phone=01604323444", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := webApp.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /v1/partners/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.True(t, span.Parent().IsRemote())
+	attrs := attributes(span)
+	assert.Equal(t, "/v1/partners/:id", attrs["http.route"].AsString())
+	assert.Equal(t, int64(200), attrs["http.status_code"].AsInt64())
+	assert.Equal(t, "GET", attrs["http.method"].AsString())
+	assert.Equal(t, "/v1/partners/1", attrs["http.target"].AsString())
+}
+
+func TestServerSpanOfErrors(t *testing.T) {
+	recorder := recordSpans(t)
+	webApp := testApp()
+
+	for _, route := range []string{"/v1/partners/2", "/not-found", "/unavailable"} {
+		_, err := webApp.Test(httptest.NewRequest("GET", route, nil), -1)
+		require.NoError(t, err)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "GET /v1/partners/:id", spans[0].Name())
+	assert.Equal(t, int64(404), attributes(spans[0])["http.status_code"].AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	// a new trace is started without traceparent
+	assert.False(t, spans[0].Parent().IsValid())
+
+	assert.Equal(t, "HTTP GET", spans[1].Name())
+	assert.NotContains(t, attributes(spans[1]), attribute.Key("http.route"))
+
+	assert.Equal(t, "GET /unavailable", spans[2].Name())
+	assert.Equal(t, int64(503), attributes(spans[2])["http.status_code"].AsInt64())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), "jaeger")
+	assert.Error(t, err)
+
+	shutdown, err := tracing.Setup(context.Background(), tracing.Stdout)
+	require.NoError(t, err)
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+	assert.NoError(t, shutdown(context.Background()))
+}